@@ -0,0 +1,97 @@
+package authz
+
+import (
+	"errors"
+
+	"cisdi-test-cms/middleware"
+	"cisdi-test-cms/models"
+)
+
+var ErrUnauthorized = errors.New("unauthorized")
+
+type Action string
+
+const (
+	ActionCreate       Action = "article:create"
+	ActionView         Action = "article:view"
+	ActionEdit         Action = "article:edit"          // buat versi baru, revert
+	ActionChangeStatus Action = "article:change_status" // ubah status, jadwal publish/unpublish
+	ActionReview       Action = "article:review"        // approve / request changes
+	ActionDelete       Action = "article:delete"
+	ActionTransfer     Action = "article:transfer" // pindahkan artikel ke author lain
+	ActionFeature      Action = "article:feature"  // tandai artikel featured
+	ActionManageTrash  Action = "article:trash"    // lihat, restore, purge artikel terhapus
+)
+
+type scope int
+
+const (
+	scopeOwn scope = iota + 1 // hanya artikel milik sendiri
+	scopeAny                  // semua artikel
+)
+
+// policies memetakan role ke aksi yang boleh dilakukan beserta cakupannya.
+var policies = map[models.UserRole]map[Action]scope{
+	models.RoleWriter: {
+		ActionCreate:       scopeAny,
+		ActionView:         scopeOwn,
+		ActionEdit:         scopeOwn,
+		ActionChangeStatus: scopeOwn,
+		ActionDelete:       scopeOwn,
+	},
+	models.RoleEditor: {
+		ActionCreate:       scopeAny,
+		ActionView:         scopeAny,
+		ActionEdit:         scopeAny,
+		ActionChangeStatus: scopeAny,
+		ActionReview:       scopeAny,
+		ActionDelete:       scopeOwn,
+		ActionFeature:      scopeAny,
+	},
+	models.RoleAdmin: {
+		ActionCreate:       scopeAny,
+		ActionView:         scopeAny,
+		ActionEdit:         scopeAny,
+		ActionChangeStatus: scopeAny,
+		ActionReview:       scopeAny,
+		ActionDelete:       scopeAny,
+		ActionTransfer:     scopeAny,
+		ActionFeature:      scopeAny,
+		ActionManageTrash:  scopeAny,
+	},
+}
+
+// User is the caller an authorization decision is made for.
+type User struct {
+	ID   uint
+	Role models.UserRole
+}
+
+// Guest is the anonymous caller of public endpoints.
+var Guest = User{}
+
+func FromClaims(claims *middleware.Claims) User {
+	return User{ID: claims.UserID, Role: models.UserRole(claims.Role)}
+}
+
+// Can reports whether user may perform action on article. A nil article asks
+// whether the action is allowed on every article, regardless of its author.
+func Can(user User, action Action, article *models.Article) bool {
+	if user.ID == 0 {
+		return false
+	}
+
+	s, ok := policies[user.Role][action]
+	if !ok {
+		return false
+	}
+
+	switch s {
+	case scopeAny:
+		return true
+	case scopeOwn:
+		return article != nil && article.AuthorID == user.ID
+	default:
+		return false
+	}
+}
@@ -0,0 +1,54 @@
+// Command merge-duplicate-tags menggabungkan tag yang sudah ada dan namanya sama
+// setelah normalisasi (mis. "Go", "go " dan "GO"). Jalankan sekali setelah
+// normalisasi nama tag diaktifkan; gunakan -dry-run untuk melihat rencananya.
+package main
+
+import (
+	"flag"
+	"log"
+
+	"cisdi-test-cms/config"
+	"cisdi-test-cms/repositories"
+	"cisdi-test-cms/services"
+
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	dryRun := flag.Bool("dry-run", false, "only print the tags that would be merged")
+	flag.Parse()
+
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found")
+	}
+
+	db := config.InitDB()
+
+	tagRepo := repositories.NewTagRepository(db)
+	articleRepo := repositories.NewArticleRepository(db)
+	tagNormalizer := services.NewTagNormalizer(config.TagNameMaxLength(), config.TagCaseFolding(), config.TagDisallowedChars())
+	relationshipScorer, err := services.NewRelationshipScorer(config.RelationshipScorer())
+	if err != nil {
+		log.Fatal(err)
+	}
+	tagService := services.NewTagService(tagRepo, articleRepo, tagNormalizer, relationshipScorer)
+
+	groups, err := tagService.MergeDuplicateTags(*dryRun)
+	for _, group := range groups {
+		for _, duplicate := range group.Duplicates {
+			log.Printf("merge tag %d %q into %d %q", duplicate.ID, duplicate.Name, group.Canonical.ID, group.Canonical.Name)
+		}
+		if group.Canonical.Name != group.Name {
+			log.Printf("rename tag %d %q to %q", group.Canonical.ID, group.Canonical.Name, group.Name)
+		}
+	}
+	if err != nil {
+		log.Fatalf("merge duplicate tags: %v", err)
+	}
+
+	if *dryRun {
+		log.Printf("dry run: %d tag groups would be updated", len(groups))
+		return
+	}
+	log.Printf("updated %d tag groups", len(groups))
+}
@@ -0,0 +1,29 @@
+// Command rebuild-tag-stats menghitung ulang tag_stats dan tag_pair_stats dari
+// latest version semua artikel. Jalankan setelah migrasi 012 di database yang
+// sudah berisi artikel, atau jika statistik diduga tidak sinkron.
+package main
+
+import (
+	"log"
+	"time"
+
+	"cisdi-test-cms/config"
+	"cisdi-test-cms/repositories"
+
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found")
+	}
+
+	db := config.InitDB()
+	articleRepo := repositories.NewArticleRepository(db)
+
+	start := time.Now()
+	if err := articleRepo.RebuildTagStats(); err != nil {
+		log.Fatalf("rebuild tag stats: %v", err)
+	}
+	log.Printf("rebuilt tag stats in %s", time.Since(start).Round(time.Millisecond))
+}
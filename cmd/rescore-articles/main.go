@@ -0,0 +1,43 @@
+// Command rescore-articles menghitung ulang article_tag_relationship_score semua
+// versi artikel dengan scorer dari RELATIONSHIP_SCORER. Jalankan setelah
+// algoritma diganti agar skor lama dan baru bisa dibandingkan.
+package main
+
+import (
+	"log"
+	"time"
+
+	"cisdi-test-cms/config"
+	"cisdi-test-cms/repositories"
+	"cisdi-test-cms/services"
+
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found")
+	}
+
+	scorer, err := services.NewRelationshipScorer(config.RelationshipScorer())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	db := config.InitDB()
+
+	articleRepo := repositories.NewArticleRepository(db)
+	tagRepo := repositories.NewTagRepository(db)
+	articleVersionRepo := repositories.NewArticleVersionRepository(db)
+	unitOfWork := repositories.NewUnitOfWork(db)
+	contentRenderer := services.NewContentRenderer(config.ContentRenderCacheSize())
+	tagNormalizer := services.NewTagNormalizer(config.TagNameMaxLength(), config.TagCaseFolding(), config.TagDisallowedChars())
+	articleService := services.NewArticleService(articleRepo, tagRepo, articleVersionRepo, unitOfWork, contentRenderer, tagNormalizer, scorer)
+
+	start := time.Now()
+	rescored, err := articleService.RescoreArticleVersions()
+	if err != nil {
+		log.Fatalf("rescore article versions (%d done): %v", rescored, err)
+	}
+	log.Printf("rescored %d article versions with %s in %s", rescored, scorer.Name(), time.Since(start).Round(time.Millisecond))
+}
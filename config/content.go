@@ -0,0 +1,6 @@
+package config
+
+// ContentRenderCacheSize is the number of rendered article versions kept in memory.
+func ContentRenderCacheSize() int {
+	return getIntEnv("CONTENT_RENDER_CACHE_SIZE", 1000)
+}
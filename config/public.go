@@ -0,0 +1,36 @@
+package config
+
+import "time"
+
+// PublicBaseURL is the absolute base URL used for links in feeds and sitemaps.
+// When empty, the base URL is derived from the incoming request.
+func PublicBaseURL() string {
+	return getEnv("PUBLIC_BASE_URL", "")
+}
+
+// FeedTitle is the channel title of the RSS and Atom feeds.
+func FeedTitle() string {
+	return getEnv("FEED_TITLE", "CMS Articles")
+}
+
+// FeedItemLimit is the default and maximum number of items in a feed.
+func FeedItemLimit() int {
+	return getIntEnv("FEED_ITEM_LIMIT", 20)
+}
+
+// RelatedArticleLimit is the default and maximum number of related articles.
+func RelatedArticleLimit() int {
+	return getIntEnv("RELATED_ARTICLE_LIMIT", 5)
+}
+
+// RelatedArticleHalfLife enables recency decay for related articles: the score
+// of an article halves every half-life since it was published. Zero disables it.
+func RelatedArticleHalfLife() time.Duration {
+	return getDurationEnv("RELATED_ARTICLE_HALF_LIFE", 0)
+}
+
+// SitemapCacheTTL is how long a built sitemap is served from memory before it
+// is rebuilt, so changes made by other replicas are picked up. Zero disables it.
+func SitemapCacheTTL() time.Duration {
+	return getDurationEnv("SITEMAP_CACHE_TTL", 5*time.Minute)
+}
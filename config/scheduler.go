@@ -0,0 +1,19 @@
+package config
+
+import "time"
+
+// SchedulerInterval controls how often scheduled publish/unpublish jobs poll for due versions.
+func SchedulerInterval() time.Duration {
+	return getDurationEnv("SCHEDULER_INTERVAL", 30*time.Second)
+}
+
+// TrashRetention is how long soft-deleted articles stay in the trash before
+// the retention job purges them permanently.
+func TrashRetention() time.Duration {
+	return getDurationEnv("TRASH_RETENTION", 30*24*time.Hour)
+}
+
+// TrashPurgeInterval controls how often the retention job looks for expired trash.
+func TrashPurgeInterval() time.Duration {
+	return getDurationEnv("TRASH_PURGE_INTERVAL", time.Hour)
+}
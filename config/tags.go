@@ -0,0 +1,33 @@
+package config
+
+import "time"
+
+// TagNameMaxLength is the maximum length (in characters) of a normalized tag name.
+func TagNameMaxLength() int {
+	return getIntEnv("TAG_NAME_MAX_LENGTH", 50)
+}
+
+// TagCaseFolding makes tag names case-insensitive ("Go" and "go" become one tag).
+func TagCaseFolding() bool {
+	return getBoolEnv("TAG_CASE_FOLDING", true)
+}
+
+// TagDisallowedChars lists characters that are rejected in tag names.
+func TagDisallowedChars() string {
+	return getEnv("TAG_DISALLOWED_CHARS", `,;<>"\`)
+}
+
+// TagTrendingInterval controls how often usage_count and trending_score of tags are recomputed.
+func TagTrendingInterval() time.Duration {
+	return getDurationEnv("TAG_TRENDING_INTERVAL", 5*time.Minute)
+}
+
+// TagTrendingHalfLife is how long it takes the trending score of an unused tag to halve.
+func TagTrendingHalfLife() time.Duration {
+	return getDurationEnv("TAG_TRENDING_HALF_LIFE", 7*24*time.Hour)
+}
+
+// RelationshipScorer selects the algorithm for article_tag_relationship_score: pmi, ppmi, npmi or jaccard.
+func RelationshipScorer() string {
+	return getEnv("RELATIONSHIP_SCORER", "pmi")
+}
@@ -1,10 +1,14 @@
 package handlers
 
 import (
+	"cisdi-test-cms/authz"
 	"cisdi-test-cms/helper"
 	"cisdi-test-cms/models"
 	"cisdi-test-cms/services"
+	"errors"
 	"net/http"
+	"net/url"
+	"path"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
@@ -20,7 +24,7 @@ func NewArticleHandler(articleService services.ArticleService) *ArticleHandler {
 }
 
 func (h *ArticleHandler) CreateArticle(c *gin.Context) {
-	userID, _ := c.Get("user_id")
+	user := currentUser(c)
 
 	var req models.CreateArticleRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -28,7 +32,7 @@ func (h *ArticleHandler) CreateArticle(c *gin.Context) {
 		return
 	}
 
-	article, err := h.articleService.CreateArticle(req, userID.(uint))
+	article, err := h.articleService.CreateArticle(req, user)
 	if err != nil {
 		h.Helper.SendBadRequest(c, "Error :", h.Helper.EmptyJsonMap())
 		return
@@ -37,11 +41,31 @@ func (h *ArticleHandler) CreateArticle(c *gin.Context) {
 	h.Helper.SendSuccess(c, "Article created successfully", article)
 }
 
+// PreviewScore menghitung article_tag_relationship_score dan rincian per
+// pasangan untuk daftar tag kandidat tanpa menyimpan apapun
+func (h *ArticleHandler) PreviewScore(c *gin.Context) {
+	user := currentUser(c)
+
+	var req models.ScoreTagsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.Helper.SendBadRequest(c, "Invalid request data", err.Error())
+		return
+	}
+
+	score, err := h.articleService.ScoreTags(req.Tags, user)
+	if err != nil {
+		h.Helper.SendBadRequest(c, "Error : ", err.Error())
+		return
+	}
+
+	h.Helper.SendSuccess(c, "Success", score)
+}
+
 func (h *ArticleHandler) GetArticles(c *gin.Context) {
-	userID, _ := c.Get("user_id")
-	role, _ := c.Get("role")
+	user := currentUser(c)
 
 	// Ambil parameter query
+	q := c.Query("q")
 	status := c.DefaultQuery("status", "published")
 	authorIDStr := c.Query("author_id")
 	tagIDStr := c.Query("tag_id")
@@ -52,7 +76,13 @@ func (h *ArticleHandler) GetArticles(c *gin.Context) {
 
 	// Konversi parameter
 	page, _ := strconv.Atoi(pageStr)
+	if page < 1 {
+		page = 1
+	}
 	limit, _ := strconv.Atoi(limitStr)
+	if limit < 1 {
+		limit = 10
+	}
 	authorID := uint(0)
 	if authorIDStr != "" {
 		aid, err := strconv.ParseUint(authorIDStr, 10, 32)
@@ -68,39 +98,34 @@ func (h *ArticleHandler) GetArticles(c *gin.Context) {
 		}
 	}
 
+	// Hasil search diurutkan berdasarkan relevansi kecuali sort_by diisi
+	if q != "" && c.Query("sort_by") == "" {
+		sortBy = "relevance"
+	}
+
 	// Siapkan params
+	includeDescendants, _ := strconv.ParseBool(c.Query("include_descendants"))
 	params := models.ArticleListParams{
-		Status:    status,
-		AuthorID:  authorID,
-		TagID:     tagID,
-		Page:      page,
-		Limit:     limit,
-		SortBy:    sortBy,
-		SortOrder: sortOrder,
-	}
-
-	// Role-based access: jika bukan admin/editor, hanya bisa akses milik sendiri atau yang published
-	isAdmin := role == "admin" || role == "editor"
-	if !isAdmin {
-		// Jika status bukan published, hanya boleh akses milik sendiri
-		if status != "published" {
-			params.AuthorID = userID.(uint)
-		}
+		Query:              q,
+		Status:             status,
+		AuthorID:           authorID,
+		TagID:              tagID,
+		IncludeDescendants: includeDescendants,
+		Page:               page,
+		Limit:              limit,
+		SortBy:             sortBy,
+		SortOrder:          sortOrder,
+		Cursor:             c.Query("cursor"),
 	}
 
-	articles, total, err := h.articleService.GetArticles(params, userID.(uint), false)
+	// Pembatasan akses berdasarkan role dilakukan oleh authz policy di service
+	articles, total, err := h.articleService.GetArticles(params, user, false)
 	if err != nil {
 		h.Helper.SendBadRequest(c, "Error : ", err.Error())
 		return
 	}
 
-	data := map[string]interface{}{
-		"articles": articles,
-		"total":    total,
-		"page":     params.Page,
-		"limit":    params.Limit,
-	}
-	h.Helper.SendSuccess(c, "Success", data)
+	h.Helper.SendSuccess(c, "Success", h.articleListData(c, params, articles, total))
 }
 
 func (h *ArticleHandler) GetPublicArticles(c *gin.Context) {
@@ -111,42 +136,60 @@ func (h *ArticleHandler) GetPublicArticles(c *gin.Context) {
 	}
 
 	// Set defaults
-	if params.Page == 0 {
+	if params.Page < 1 {
 		params.Page = 1
 	}
-	if params.Limit == 0 {
+	if params.Query != "" && c.Query("sort_by") == "" {
+		params.SortBy = "relevance"
+	}
+	if params.Limit < 1 {
 		params.Limit = 10
 	}
 
-	articles, total, err := h.articleService.GetArticles(params, 0, true)
+	articles, total, err := h.articleService.GetArticles(params, authz.Guest, true)
 	if err != nil {
 		h.Helper.SendBadRequest(c, "Error : ", err.Error())
 		return
 	}
 
+	h.Helper.SendSuccess(c, "Success", h.articleListData(c, params, articles, total))
+}
+
+// articleListData menyusun response list artikel. Pada mode page/limit berisi
+// total dan link paging; pada mode cursor Count dilewati sehingga hanya
+// next_cursor yang dikirim. next_cursor juga disertakan di mode page/limit agar
+// client bisa beralih ke cursor.
+func (h *ArticleHandler) articleListData(c *gin.Context, params models.ArticleListParams, articles []models.Article, total int64) map[string]interface{} {
 	data := map[string]interface{}{
-		"articles": articles,
-		"total":    total,
-		"page":     params.Page,
-		"limit":    params.Limit,
+		"articles":    articles,
+		"limit":       params.Limit,
+		"next_cursor": services.NextArticleCursor(params, articles),
 	}
-	h.Helper.SendSuccess(c, "Success", data)
+	if params.Cursor == "" {
+		data["total"] = total
+		data["page"] = params.Page
+		data["paging"] = h.Helper.GeneratePaging(c, params.Page-1, params.Page+1, params.Limit, params.Page, int(total))
+	}
+	return data
 }
 
 func (h *ArticleHandler) GetArticle(c *gin.Context) {
-	userID, _ := c.Get("user_id")
+	user := currentUser(c)
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
 		h.Helper.SendBadRequest(c, "Invalid article ID", h.Helper.EmptyJsonMap())
 		return
 	}
 
-	article, err := h.articleService.GetArticle(uint(id), userID.(uint), false)
+	article, err := h.articleService.GetArticle(uint(id), user, false)
 	if err != nil {
 		h.Helper.SendNotFoundError(c, err.Error(), h.Helper.EmptyJsonMap())
 		return
 	}
 
+	// ETag dipakai client sebagai If-Match saat membuat versi atau mengubah status
+	c.Header("ETag", services.ArticleETag(article))
+
 	h.Helper.SendSuccess(c, "Success", article)
 }
 
@@ -157,7 +200,7 @@ func (h *ArticleHandler) GetPublicArticle(c *gin.Context) {
 		return
 	}
 
-	article, err := h.articleService.GetArticle(uint(id), 0, true)
+	article, err := h.articleService.GetArticle(uint(id), authz.Guest, true)
 	if err != nil {
 		h.Helper.SendNotFoundError(c, err.Error(), h.Helper.EmptyJsonMap())
 		return
@@ -166,15 +209,35 @@ func (h *ArticleHandler) GetPublicArticle(c *gin.Context) {
 	h.Helper.SendSuccess(c, "Success", article)
 }
 
+func (h *ArticleHandler) GetPublicArticleBySlug(c *gin.Context) {
+	article, currentSlug, err := h.articleService.GetPublicArticleBySlug(c.Param("slug"))
+	if err != nil {
+		h.Helper.SendNotFoundErrorV2(c, err.Error(), h.Helper.EmptyJsonMap())
+		return
+	}
+
+	// Slug lama diarahkan permanen ke slug terbaru
+	if currentSlug != "" {
+		location := path.Join(path.Dir(c.Request.URL.Path), url.PathEscape(currentSlug))
+		if c.Request.URL.RawQuery != "" {
+			location += "?" + c.Request.URL.RawQuery
+		}
+		c.Redirect(http.StatusMovedPermanently, location)
+		return
+	}
+
+	h.Helper.SendSuccess(c, "Success", article)
+}
+
 func (h *ArticleHandler) DeleteArticle(c *gin.Context) {
-	userID, _ := c.Get("user_id")
+	user := currentUser(c)
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
 		h.Helper.SendBadRequest(c, "Invalid article ID", h.Helper.EmptyJsonMap())
 		return
 	}
 
-	if err := h.articleService.DeleteArticle(uint(id), userID.(uint)); err != nil {
+	if err := h.articleService.DeleteArticle(uint(id), user); err != nil {
 		h.Helper.SendBadRequest(c, "Error : ", err.Error())
 		return
 	}
@@ -182,8 +245,107 @@ func (h *ArticleHandler) DeleteArticle(c *gin.Context) {
 	h.Helper.SendSuccess(c, "Article deleted successfully", h.Helper.EmptyJsonMap())
 }
 
+func (h *ArticleHandler) UpdateArticle(c *gin.Context) {
+	user := currentUser(c)
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		h.Helper.SendBadRequest(c, "Invalid article ID", h.Helper.EmptyJsonMap())
+		return
+	}
+
+	var req models.UpdateArticleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.Helper.SendBadRequest(c, "Invalid request data ", err.Error())
+		return
+	}
+	req.IfMatch = c.GetHeader("If-Match")
+
+	article, err := h.articleService.UpdateArticle(uint(id), req, user)
+	if err != nil {
+		if h.sendPreconditionError(c, err) {
+			return
+		}
+		h.Helper.SendBadRequest(c, "Error : ", err.Error())
+		return
+	}
+
+	c.Header("ETag", services.ArticleETag(article))
+	h.Helper.SendSuccess(c, "Article updated successfully", article)
+}
+
+func (h *ArticleHandler) GetArticleAudits(c *gin.Context) {
+	user := currentUser(c)
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		h.Helper.SendBadRequest(c, "Invalid article ID", h.Helper.EmptyJsonMap())
+		return
+	}
+
+	audits, err := h.articleService.GetArticleAudits(uint(id), user)
+	if err != nil {
+		h.Helper.SendBadRequest(c, "Error : ", err.Error())
+		return
+	}
+
+	h.Helper.SendSuccess(c, "Success", audits)
+}
+
+func (h *ArticleHandler) GetTrashedArticles(c *gin.Context) {
+	user := currentUser(c)
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	articles, total, err := h.articleService.GetTrashedArticles(page, limit, user)
+	if err != nil {
+		h.Helper.SendBadRequest(c, "Error : ", err.Error())
+		return
+	}
+
+	data := map[string]interface{}{
+		"articles": articles,
+		"total":    total,
+		"page":     page,
+		"limit":    limit,
+	}
+	h.Helper.SendSuccess(c, "Success", data)
+}
+
+func (h *ArticleHandler) RestoreArticle(c *gin.Context) {
+	user := currentUser(c)
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		h.Helper.SendBadRequest(c, "Invalid article ID", h.Helper.EmptyJsonMap())
+		return
+	}
+
+	article, err := h.articleService.RestoreArticle(uint(id), user)
+	if err != nil {
+		h.Helper.SendBadRequest(c, "Error : ", err.Error())
+		return
+	}
+
+	h.Helper.SendSuccess(c, "Article restored successfully", article)
+}
+
+func (h *ArticleHandler) PurgeArticle(c *gin.Context) {
+	user := currentUser(c)
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		h.Helper.SendBadRequest(c, "Invalid article ID", h.Helper.EmptyJsonMap())
+		return
+	}
+
+	if err := h.articleService.PurgeArticle(uint(id), user); err != nil {
+		h.Helper.SendBadRequest(c, "Error : ", err.Error())
+		return
+	}
+
+	h.Helper.SendSuccess(c, "Article purged successfully", h.Helper.EmptyJsonMap())
+}
+
 func (h *ArticleHandler) CreateArticleVersion(c *gin.Context) {
-	userID, _ := c.Get("user_id")
+	user := currentUser(c)
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
 		h.Helper.SendBadRequest(c, "Invalid article ID", h.Helper.EmptyJsonMap())
@@ -195,9 +357,13 @@ func (h *ArticleHandler) CreateArticleVersion(c *gin.Context) {
 		h.Helper.SendBadRequest(c, "Invalid request data ", err.Error())
 		return
 	}
+	req.IfMatch = c.GetHeader("If-Match")
 
-	version, err := h.articleService.CreateArticleVersion(uint(id), req, userID.(uint))
+	version, err := h.articleService.CreateArticleVersion(uint(id), req, user)
 	if err != nil {
+		if h.sendPreconditionError(c, err) {
+			return
+		}
 		h.Helper.SendBadRequest(c, "Error : ", err.Error())
 		return
 	}
@@ -206,7 +372,7 @@ func (h *ArticleHandler) CreateArticleVersion(c *gin.Context) {
 }
 
 func (h *ArticleHandler) UpdateVersionStatus(c *gin.Context) {
-	userID, _ := c.Get("user_id")
+	user := currentUser(c)
 	articleID, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
 		h.Helper.SendBadRequest(c, "Invalid article ID", h.Helper.EmptyJsonMap())
@@ -224,8 +390,12 @@ func (h *ArticleHandler) UpdateVersionStatus(c *gin.Context) {
 		h.Helper.SendBadRequest(c, "Error ", err.Error())
 		return
 	}
+	req.IfMatch = c.GetHeader("If-Match")
 
-	if err := h.articleService.UpdateVersionStatus(uint(articleID), uint(versionID), req.Status, userID.(uint)); err != nil {
+	if err := h.articleService.UpdateVersionStatus(uint(articleID), uint(versionID), req, user); err != nil {
+		if h.sendPreconditionError(c, err) {
+			return
+		}
 		h.Helper.SendBadRequest(c, err.Error(), h.Helper.EmptyJsonMap())
 		return
 	}
@@ -234,14 +404,14 @@ func (h *ArticleHandler) UpdateVersionStatus(c *gin.Context) {
 }
 
 func (h *ArticleHandler) GetArticleVersions(c *gin.Context) {
-	userID, _ := c.Get("user_id")
+	user := currentUser(c)
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
 		h.Helper.SendBadRequest(c, "Invalid article ID", h.Helper.EmptyJsonMap())
 		return
 	}
 
-	versions, err := h.articleService.GetArticleVersions(uint(id), userID.(uint))
+	versions, err := h.articleService.GetArticleVersions(uint(id), user)
 	if err != nil {
 		h.Helper.SendBadRequest(c, "Error : ", err.Error())
 		return
@@ -251,7 +421,7 @@ func (h *ArticleHandler) GetArticleVersions(c *gin.Context) {
 }
 
 func (h *ArticleHandler) GetArticleVersion(c *gin.Context) {
-	userID, _ := c.Get("user_id")
+	user := currentUser(c)
 	articleID, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
 		h.Helper.SendBadRequest(c, "Invalid article ID", h.Helper.EmptyJsonMap())
@@ -264,7 +434,7 @@ func (h *ArticleHandler) GetArticleVersion(c *gin.Context) {
 		return
 	}
 
-	version, err := h.articleService.GetArticleVersion(uint(articleID), uint(versionID), userID.(uint))
+	version, err := h.articleService.GetArticleVersion(uint(articleID), uint(versionID), user)
 	if err != nil {
 		h.Helper.SendNotFoundError(c, err.Error(), h.Helper.EmptyJsonMap())
 		return
@@ -272,3 +442,175 @@ func (h *ArticleHandler) GetArticleVersion(c *gin.Context) {
 
 	h.Helper.SendSuccess(c, "Success", version)
 }
+
+func (h *ArticleHandler) DiffArticleVersions(c *gin.Context) {
+	user := currentUser(c)
+	articleID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		h.Helper.SendBadRequest(c, "Invalid article ID", h.Helper.EmptyJsonMap())
+		return
+	}
+
+	versionID, err := strconv.ParseUint(c.Param("version_id"), 10, 32)
+	if err != nil {
+		h.Helper.SendBadRequest(c, "Invalid version ID", h.Helper.EmptyJsonMap())
+		return
+	}
+
+	againstID, err := strconv.ParseUint(c.Query("against"), 10, 32)
+	if err != nil {
+		h.Helper.SendBadRequest(c, "Invalid against version ID", h.Helper.EmptyJsonMap())
+		return
+	}
+
+	diff, err := h.articleService.DiffVersions(uint(articleID), uint(versionID), uint(againstID), user)
+	if err != nil {
+		h.Helper.SendBadRequest(c, "Error : ", err.Error())
+		return
+	}
+
+	h.Helper.SendSuccess(c, "Success", diff)
+}
+
+func (h *ArticleHandler) RevertArticleVersion(c *gin.Context) {
+	user := currentUser(c)
+	articleID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		h.Helper.SendBadRequest(c, "Invalid article ID", h.Helper.EmptyJsonMap())
+		return
+	}
+
+	versionID, err := strconv.ParseUint(c.Param("version_id"), 10, 32)
+	if err != nil {
+		h.Helper.SendBadRequest(c, "Invalid version ID", h.Helper.EmptyJsonMap())
+		return
+	}
+
+	// Body opsional, precondition boleh dikirim lewat header If-Match saja
+	var req models.RevertArticleVersionRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			h.Helper.SendBadRequest(c, "Invalid request data ", err.Error())
+			return
+		}
+	}
+	req.IfMatch = c.GetHeader("If-Match")
+
+	version, err := h.articleService.RevertArticleVersion(uint(articleID), uint(versionID), req, user)
+	if err != nil {
+		if h.sendPreconditionError(c, err) {
+			return
+		}
+		h.Helper.SendBadRequest(c, "Error : ", err.Error())
+		return
+	}
+
+	h.Helper.SendSuccess(c, "Version reverted successfully", version)
+}
+
+func (h *ArticleHandler) ScheduleVersion(c *gin.Context) {
+	user := currentUser(c)
+	articleID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		h.Helper.SendBadRequest(c, "Invalid article ID", h.Helper.EmptyJsonMap())
+		return
+	}
+
+	versionID, err := strconv.ParseUint(c.Param("version_id"), 10, 32)
+	if err != nil {
+		h.Helper.SendBadRequest(c, "Invalid version ID", h.Helper.EmptyJsonMap())
+		return
+	}
+
+	var req models.ScheduleVersionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.Helper.SendBadRequest(c, "Invalid request data ", err.Error())
+		return
+	}
+
+	version, err := h.articleService.ScheduleVersion(uint(articleID), uint(versionID), req, user)
+	if err != nil {
+		h.Helper.SendBadRequest(c, "Error : ", err.Error())
+		return
+	}
+
+	h.Helper.SendSuccess(c, "Version scheduled successfully", version)
+}
+
+func (h *ArticleHandler) CancelVersionSchedule(c *gin.Context) {
+	user := currentUser(c)
+	articleID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		h.Helper.SendBadRequest(c, "Invalid article ID", h.Helper.EmptyJsonMap())
+		return
+	}
+
+	versionID, err := strconv.ParseUint(c.Param("version_id"), 10, 32)
+	if err != nil {
+		h.Helper.SendBadRequest(c, "Invalid version ID", h.Helper.EmptyJsonMap())
+		return
+	}
+
+	if err := h.articleService.CancelVersionSchedule(uint(articleID), uint(versionID), user); err != nil {
+		h.Helper.SendBadRequest(c, "Error : ", err.Error())
+		return
+	}
+
+	h.Helper.SendSuccess(c, "Version schedule cancelled successfully", h.Helper.EmptyJsonMap())
+}
+
+func (h *ArticleHandler) GetPendingSchedules(c *gin.Context) {
+	user := currentUser(c)
+
+	versions, err := h.articleService.GetPendingSchedules(user)
+	if err != nil {
+		h.Helper.SendBadRequest(c, "Error : ", err.Error())
+		return
+	}
+
+	h.Helper.SendSuccess(c, "Success", versions)
+}
+
+func (h *ArticleHandler) GetVersionReviews(c *gin.Context) {
+	user := currentUser(c)
+	articleID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		h.Helper.SendBadRequest(c, "Invalid article ID", h.Helper.EmptyJsonMap())
+		return
+	}
+
+	versionID, err := strconv.ParseUint(c.Param("version_id"), 10, 32)
+	if err != nil {
+		h.Helper.SendBadRequest(c, "Invalid version ID", h.Helper.EmptyJsonMap())
+		return
+	}
+
+	reviews, err := h.articleService.GetVersionReviews(uint(articleID), uint(versionID), user)
+	if err != nil {
+		h.Helper.SendBadRequest(c, "Error : ", err.Error())
+		return
+	}
+
+	h.Helper.SendSuccess(c, "Success", reviews)
+}
+
+// sendPreconditionError menangani error optimistic concurrency: 428 jika
+// If-Match/base_version_id tidak dikirim, 412 beserta latest version jika basis
+// request sudah usang. Mengembalikan false untuk error lain.
+func (h *ArticleHandler) sendPreconditionError(c *gin.Context, err error) bool {
+	if errors.Is(err, services.ErrPreconditionRequired) {
+		h.Helper.SendPreconditionRequired(c, err.Error(), h.Helper.EmptyJsonMap())
+		return true
+	}
+
+	var stale *services.StaleVersionError
+	if errors.As(err, &stale) {
+		c.Header("ETag", stale.ETag)
+		h.Helper.SendPreconditionFailed(c, err.Error(), map[string]interface{}{
+			"latest_version": stale.Latest,
+		})
+		return true
+	}
+
+	return false
+}
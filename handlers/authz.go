@@ -0,0 +1,18 @@
+package handlers
+
+import (
+	"cisdi-test-cms/authz"
+	"cisdi-test-cms/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// currentUser membangun principal authz dari claims JWT yang diset AuthMiddleware.
+// Request tanpa token (route public) diperlakukan sebagai guest.
+func currentUser(c *gin.Context) authz.User {
+	claims, ok := middleware.GetClaims(c)
+	if !ok {
+		return authz.Guest
+	}
+	return authz.FromClaims(claims)
+}
@@ -0,0 +1,244 @@
+package handlers
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"cisdi-test-cms/config"
+	"cisdi-test-cms/helper"
+	"cisdi-test-cms/models"
+	"cisdi-test-cms/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FeedHandler struct {
+	feedService services.FeedService
+	Helper      *helper.HTTPHelper
+}
+
+func NewFeedHandler(feedService services.FeedService) *FeedHandler {
+	return &FeedHandler{feedService: feedService}
+}
+
+type rssDocument struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	AtomNS  string     `xml:"xmlns:atom,attr"`
+	DCNS    string     `xml:"xmlns:dc,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	AtomLink      atomLink  `xml:"atom:link"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link"`
+	GUID        rssGUID  `xml:"guid"`
+	Description string   `xml:"description"`
+	Creator     string   `xml:"dc:creator,omitempty"`
+	Categories  []string `xml:"category"`
+	PubDate     string   `xml:"pubDate"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Link       atomLink       `xml:"link"`
+	Published  string         `xml:"published"`
+	Updated    string         `xml:"updated"`
+	Author     atomPerson     `xml:"author"`
+	Categories []atomCategory `xml:"category"`
+	Summary    string         `xml:"summary"`
+	Content    atomText       `xml:"content"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomText struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+func (h *FeedHandler) GetRSSFeed(c *gin.Context) {
+	feed, ok := h.loadFeed(c, "rss")
+	if !ok {
+		return
+	}
+
+	base := publicBaseURL(c)
+	doc := rssDocument{
+		Version: "2.0",
+		AtomNS:  "http://www.w3.org/2005/Atom",
+		DCNS:    "http://purl.org/dc/elements/1.1/",
+		Channel: rssChannel{
+			Title:       config.FeedTitle(),
+			Link:        base + "/api/v1/public/articles",
+			Description: "Artikel terbaru yang dipublikasikan",
+			AtomLink:    atomLink{Href: base + c.Request.URL.RequestURI(), Rel: "self", Type: "application/rss+xml"},
+			Items:       []rssItem{},
+		},
+	}
+	if !feed.Updated.IsZero() {
+		doc.Channel.LastBuildDate = feed.Updated.UTC().Format(time.RFC1123Z)
+	}
+
+	for _, item := range feed.Items {
+		doc.Channel.Items = append(doc.Channel.Items, rssItem{
+			Title:       item.Title,
+			Link:        publicArticleURL(base, item.Slug),
+			GUID:        rssGUID{IsPermaLink: false, Value: fmt.Sprintf("article-%d", item.ArticleID)},
+			Description: item.Content,
+			// <author> RSS harus berupa email, nama penulis memakai dc:creator
+			Creator:    item.AuthorName,
+			Categories: item.Tags,
+			PubDate:    item.PublishedAt.UTC().Format(time.RFC1123Z),
+		})
+	}
+
+	h.renderXML(c, "application/rss+xml; charset=utf-8", doc)
+}
+
+func (h *FeedHandler) GetAtomFeed(c *gin.Context) {
+	feed, ok := h.loadFeed(c, "atom")
+	if !ok {
+		return
+	}
+
+	base := publicBaseURL(c)
+	updated := feed.Updated
+	if updated.IsZero() {
+		updated = time.Unix(0, 0)
+	}
+
+	doc := atomFeed{
+		ID:      base + c.Request.URL.RequestURI(),
+		Title:   config.FeedTitle(),
+		Updated: updated.UTC().Format(time.RFC3339),
+		Links: []atomLink{
+			{Href: base + c.Request.URL.RequestURI(), Rel: "self", Type: "application/atom+xml"},
+			{Href: base + "/api/v1/public/articles", Rel: "alternate"},
+		},
+		Entries: []atomEntry{},
+	}
+
+	for _, item := range feed.Items {
+		categories := make([]atomCategory, 0, len(item.Tags))
+		for _, tag := range item.Tags {
+			categories = append(categories, atomCategory{Term: tag})
+		}
+
+		doc.Entries = append(doc.Entries, atomEntry{
+			ID:         fmt.Sprintf("%s/api/v1/public/articles/%d", base, item.ArticleID),
+			Title:      item.Title,
+			Link:       atomLink{Href: publicArticleURL(base, item.Slug), Rel: "alternate"},
+			Published:  item.PublishedAt.UTC().Format(time.RFC3339),
+			Updated:    item.UpdatedAt.UTC().Format(time.RFC3339),
+			Author:     atomPerson{Name: item.AuthorName},
+			Categories: categories,
+			Summary:    item.Summary,
+			Content:    atomText{Type: "html", Value: item.Content},
+		})
+	}
+
+	h.renderXML(c, "application/atom+xml; charset=utf-8", doc)
+}
+
+// loadFeed mengambil data feed dan menangani conditional GET. Jika ETag atau
+// Last-Modified masih sama, response 304 langsung dikirim dan ok bernilai false.
+func (h *FeedHandler) loadFeed(c *gin.Context, format string) (*models.Feed, bool) {
+	var params models.FeedParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		h.Helper.SendBadRequest(c, "Error : ", err.Error())
+		return nil, false
+	}
+
+	feed, err := h.feedService.GetFeed(params)
+	if err != nil {
+		h.Helper.SendBadRequest(c, "Error : ", err.Error())
+		return nil, false
+	}
+
+	etag := fmt.Sprintf(`"%s-%s"`, format, feed.Version)
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "public, max-age=300")
+	if !feed.Updated.IsZero() {
+		c.Header("Last-Modified", feed.Updated.UTC().Format(http.TimeFormat))
+	}
+
+	if notModified(c, etag, feed.Updated) {
+		c.Status(http.StatusNotModified)
+		return nil, false
+	}
+
+	return feed, true
+}
+
+// notModified mengikuti RFC 7232: If-None-Match diprioritaskan, If-Modified-Since
+// hanya dicek jika If-None-Match tidak dikirim.
+func notModified(c *gin.Context, etag string, lastModified time.Time) bool {
+	if inm := c.GetHeader("If-None-Match"); inm != "" {
+		for _, candidate := range strings.Split(inm, ",") {
+			candidate = strings.TrimSpace(candidate)
+			if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+				return true
+			}
+		}
+		return false
+	}
+
+	if ims := c.GetHeader("If-Modified-Since"); ims != "" && !lastModified.IsZero() {
+		since, err := http.ParseTime(ims)
+		if err == nil && !lastModified.Truncate(time.Second).After(since) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (h *FeedHandler) renderXML(c *gin.Context, contentType string, doc interface{}) {
+	out, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		h.Helper.SendBadRequest(c, "Error : ", err.Error())
+		return
+	}
+
+	c.Data(http.StatusOK, contentType, append([]byte(xml.Header), out...))
+}
@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"cisdi-test-cms/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+// publicBaseURL mengembalikan base URL absolut untuk link di feed dan sitemap.
+// PUBLIC_BASE_URL dipakai jika diset, selain itu diturunkan dari request.
+func publicBaseURL(c *gin.Context) string {
+	if base := config.PublicBaseURL(); base != "" {
+		return strings.TrimRight(base, "/")
+	}
+
+	scheme := "http"
+	if c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return fmt.Sprintf("%s://%s", scheme, c.Request.Host)
+}
+
+func publicArticleURL(base, slug string) string {
+	return base + "/api/v1/public/articles/by-slug/" + url.PathEscape(slug)
+}
@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"strconv"
+
+	"cisdi-test-cms/helper"
+	"cisdi-test-cms/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RelatedArticleHandler struct {
+	relatedService services.RelatedArticleService
+	Helper         *helper.HTTPHelper
+}
+
+func NewRelatedArticleHandler(relatedService services.RelatedArticleService) *RelatedArticleHandler {
+	return &RelatedArticleHandler{relatedService: relatedService}
+}
+
+// GetRelatedArticles mengembalikan artikel published lain yang terkait lewat tag.
+// Query limit opsional, dibatasi RELATED_ARTICLE_LIMIT.
+func (h *RelatedArticleHandler) GetRelatedArticles(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		h.Helper.SendBadRequest(c, "Invalid article ID", h.Helper.EmptyJsonMap())
+		return
+	}
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	related, err := h.relatedService.GetRelatedArticles(uint(id), limit)
+	if err != nil {
+		h.Helper.SendNotFoundErrorV2(c, err.Error(), h.Helper.EmptyJsonMap())
+		return
+	}
+
+	h.Helper.SendSuccess(c, "Success", related)
+}
@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"time"
+
+	"cisdi-test-cms/helper"
+	"cisdi-test-cms/models"
+	"cisdi-test-cms/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+const sitemapNS = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+type SitemapHandler struct {
+	sitemapService services.SitemapService
+	Helper         *helper.HTTPHelper
+}
+
+func NewSitemapHandler(sitemapService services.SitemapService) *SitemapHandler {
+	return &SitemapHandler{sitemapService: sitemapService}
+}
+
+type sitemapURLSet struct {
+	XMLName xml.Name     `xml:"urlset"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+type sitemapURL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+type sitemapIndex struct {
+	XMLName  xml.Name     `xml:"sitemapindex"`
+	XMLNS    string       `xml:"xmlns,attr"`
+	Sitemaps []sitemapURL `xml:"sitemap"`
+}
+
+// GetSitemap melayani /sitemap.xml. Jika URL muat dalam satu file, isinya
+// langsung berupa urlset; selain itu berupa sitemap index ke /sitemaps/sitemap-N.xml.
+func (h *SitemapHandler) GetSitemap(c *gin.Context) {
+	sitemap, err := h.sitemapService.GetSitemap()
+	if err != nil {
+		h.Helper.SendBadRequest(c, "Error : ", err.Error())
+		return
+	}
+
+	base := publicBaseURL(c)
+	if len(sitemap.Pages) <= 1 {
+		var entries []models.SitemapEntry
+		if len(sitemap.Pages) == 1 {
+			entries = sitemap.Pages[0]
+		}
+		h.renderXML(c, sitemap, buildURLSet(base, entries))
+		return
+	}
+
+	index := sitemapIndex{XMLNS: sitemapNS}
+	for i, page := range sitemap.Pages {
+		var lastMod time.Time
+		for _, entry := range page {
+			if entry.LastMod.After(lastMod) {
+				lastMod = entry.LastMod
+			}
+		}
+		index.Sitemaps = append(index.Sitemaps, sitemapURL{
+			Loc:     fmt.Sprintf("%s/sitemaps/sitemap-%d.xml", base, i+1),
+			LastMod: formatLastMod(lastMod),
+		})
+	}
+	h.renderXML(c, sitemap, index)
+}
+
+// GetSitemapPage melayani /sitemaps/sitemap-N.xml yang direferensikan sitemap index.
+func (h *SitemapHandler) GetSitemapPage(c *gin.Context) {
+	var page int
+	if _, err := fmt.Sscanf(c.Param("name"), "sitemap-%d.xml", &page); err != nil || page < 1 {
+		h.Helper.SendNotFoundErrorV2(c, "sitemap not found", h.Helper.EmptyJsonMap())
+		return
+	}
+
+	sitemap, err := h.sitemapService.GetSitemap()
+	if err != nil {
+		h.Helper.SendBadRequest(c, "Error : ", err.Error())
+		return
+	}
+
+	if page > len(sitemap.Pages) {
+		h.Helper.SendNotFoundErrorV2(c, "sitemap not found", h.Helper.EmptyJsonMap())
+		return
+	}
+
+	h.renderXML(c, sitemap, buildURLSet(publicBaseURL(c), sitemap.Pages[page-1]))
+}
+
+func buildURLSet(base string, entries []models.SitemapEntry) sitemapURLSet {
+	set := sitemapURLSet{XMLNS: sitemapNS, URLs: []sitemapURL{}}
+	for _, entry := range entries {
+		set.URLs = append(set.URLs, sitemapURL{
+			Loc:     base + entry.Path,
+			LastMod: formatLastMod(entry.LastMod),
+		})
+	}
+	return set
+}
+
+func formatLastMod(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func (h *SitemapHandler) renderXML(c *gin.Context, sitemap *models.Sitemap, doc interface{}) {
+	out, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		h.Helper.SendBadRequest(c, "Error : ", err.Error())
+		return
+	}
+
+	c.Header("Last-Modified", sitemap.GeneratedAt.UTC().Format(http.TimeFormat))
+	c.Header("Cache-Control", "public, max-age=3600")
+	c.Data(http.StatusOK, "application/xml; charset=utf-8", append([]byte(xml.Header), out...))
+}
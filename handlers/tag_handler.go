@@ -5,23 +5,33 @@ import (
 	"cisdi-test-cms/models"
 	"cisdi-test-cms/services"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
 type TagHandler struct {
-	tagService services.TagService
-	Helper     *helper.HTTPHelper
+	tagService      services.TagService
+	trendingService services.TagTrendingService
+	Helper          *helper.HTTPHelper
 }
 
-func NewTagHandler(tagService services.TagService) *TagHandler {
-	return &TagHandler{tagService: tagService}
+func NewTagHandler(tagService services.TagService, trendingService services.TagTrendingService) *TagHandler {
+	return &TagHandler{tagService: tagService, trendingService: trendingService}
 }
 
-func (h *TagHandler) CreateTag(c *gin.Context) {
+// requireAdmin mengirim 401 dan mengembalikan false jika pemanggil bukan admin.
+func (h *TagHandler) requireAdmin(c *gin.Context, message string) bool {
 	role, _ := c.Get("role")
 	if role != "admin" {
-		h.Helper.SendUnauthorizedError(c, "Only admin can create tag", h.Helper.EmptyJsonMap())
+		h.Helper.SendUnauthorizedError(c, message, h.Helper.EmptyJsonMap())
+		return false
+	}
+	return true
+}
+
+func (h *TagHandler) CreateTag(c *gin.Context) {
+	if !h.requireAdmin(c, "Only admin can create tag") {
 		return
 	}
 	var req models.CreateTagRequest
@@ -64,3 +74,206 @@ func (h *TagHandler) GetTag(c *gin.Context) {
 
 	h.Helper.SendSuccess(c, "Success", tag)
 }
+
+func (h *TagHandler) RenameTag(c *gin.Context) {
+	if !h.requireAdmin(c, "Only admin can rename tag") {
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		h.Helper.SendBadRequest(c, "Invalid tag ID", h.Helper.EmptyJsonMap())
+		return
+	}
+
+	var req models.RenameTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.Helper.SendBadRequest(c, "Error ", err.Error())
+		return
+	}
+
+	tag, err := h.tagService.RenameTag(uint(id), req)
+	if err != nil {
+		h.Helper.SendBadRequest(c, "Error ", err.Error())
+		return
+	}
+
+	h.Helper.SendSuccess(c, "Tag renamed successfully", tag)
+}
+
+func (h *TagHandler) MergeTag(c *gin.Context) {
+	if !h.requireAdmin(c, "Only admin can merge tag") {
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		h.Helper.SendBadRequest(c, "Invalid tag ID", h.Helper.EmptyJsonMap())
+		return
+	}
+
+	var req models.MergeTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.Helper.SendBadRequest(c, "Error ", err.Error())
+		return
+	}
+
+	tag, err := h.tagService.MergeTag(uint(id), req)
+	if err != nil {
+		h.Helper.SendBadRequest(c, "Error ", err.Error())
+		return
+	}
+
+	h.Helper.SendSuccess(c, "Tag merged successfully", tag)
+}
+
+func (h *TagHandler) DeleteTag(c *gin.Context) {
+	if !h.requireAdmin(c, "Only admin can delete tag") {
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		h.Helper.SendBadRequest(c, "Invalid tag ID", h.Helper.EmptyJsonMap())
+		return
+	}
+
+	if err := h.tagService.DeleteTag(uint(id)); err != nil {
+		h.Helper.SendBadRequest(c, "Error ", err.Error())
+		return
+	}
+
+	h.Helper.SendSuccess(c, "Tag deleted successfully", h.Helper.EmptyJsonMap())
+}
+
+func (h *TagHandler) AddTagAlias(c *gin.Context) {
+	if !h.requireAdmin(c, "Only admin can manage tag aliases") {
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		h.Helper.SendBadRequest(c, "Invalid tag ID", h.Helper.EmptyJsonMap())
+		return
+	}
+
+	var req models.CreateTagAliasRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.Helper.SendBadRequest(c, "Error ", err.Error())
+		return
+	}
+
+	tag, err := h.tagService.AddTagAlias(uint(id), req)
+	if err != nil {
+		h.Helper.SendBadRequest(c, "Error ", err.Error())
+		return
+	}
+
+	h.Helper.SendSuccess(c, "Alias created successfully", tag)
+}
+
+func (h *TagHandler) RemoveTagAlias(c *gin.Context) {
+	if !h.requireAdmin(c, "Only admin can manage tag aliases") {
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		h.Helper.SendBadRequest(c, "Invalid tag ID", h.Helper.EmptyJsonMap())
+		return
+	}
+	aliasID, err := strconv.ParseUint(c.Param("alias_id"), 10, 32)
+	if err != nil {
+		h.Helper.SendBadRequest(c, "Invalid alias ID", h.Helper.EmptyJsonMap())
+		return
+	}
+
+	if err := h.tagService.RemoveTagAlias(uint(id), uint(aliasID)); err != nil {
+		h.Helper.SendBadRequest(c, "Error ", err.Error())
+		return
+	}
+
+	h.Helper.SendSuccess(c, "Alias deleted successfully", h.Helper.EmptyJsonMap())
+}
+
+func (h *TagHandler) GetTagTree(c *gin.Context) {
+	tree, err := h.tagService.GetTagTree()
+	if err != nil {
+		h.Helper.SendBadRequest(c, "Error ", err.Error())
+		return
+	}
+
+	h.Helper.SendSuccess(c, "Success", tree)
+}
+
+// SuggestTags mengembalikan saran tag untuk draft dari tag yang sudah dipilih
+// dan/atau isi draft
+func (h *TagHandler) SuggestTags(c *gin.Context) {
+	var req models.SuggestTagsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.Helper.SendBadRequest(c, "Error ", err.Error())
+		return
+	}
+
+	suggestions, err := h.tagService.SuggestTags(req)
+	if err != nil {
+		h.Helper.SendBadRequest(c, "Error ", err.Error())
+		return
+	}
+
+	h.Helper.SendSuccess(c, "Success", suggestions)
+}
+
+func (h *TagHandler) SetTagParent(c *gin.Context) {
+	if !h.requireAdmin(c, "Only admin can move tag") {
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		h.Helper.SendBadRequest(c, "Invalid tag ID", h.Helper.EmptyJsonMap())
+		return
+	}
+
+	var req models.SetTagParentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.Helper.SendBadRequest(c, "Error ", err.Error())
+		return
+	}
+
+	tag, err := h.tagService.SetTagParent(uint(id), req)
+	if err != nil {
+		h.Helper.SendBadRequest(c, "Error ", err.Error())
+		return
+	}
+
+	h.Helper.SendSuccess(c, "Tag moved successfully", tag)
+}
+
+// RecomputeTrending menghitung ulang usage_count dan trending_score semua tag
+// saat itu juga, tanpa menunggu job berikutnya
+func (h *TagHandler) RecomputeTrending(c *gin.Context) {
+	if !h.requireAdmin(c, "Only admin can recompute tag trending scores") {
+		return
+	}
+
+	result, err := h.trendingService.RecomputeTagUsage(time.Now())
+	if err != nil {
+		h.Helper.SendBadRequest(c, "Error ", err.Error())
+		return
+	}
+
+	h.Helper.SendSuccess(c, "Tag trending scores recomputed", result)
+}
+
+// GetTrendingTags mengembalikan tag yang paling sering dipublikasikan di window
+// 24h, 7d atau 30d beserta velocity terhadap window sebelumnya
+func (h *TagHandler) GetTrendingTags(c *gin.Context) {
+	var params models.TrendingTagParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		h.Helper.SendBadRequest(c, "Error ", err.Error())
+		return
+	}
+
+	trending, err := h.trendingService.GetTrendingTags(params, time.Now())
+	if err != nil {
+		h.Helper.SendBadRequest(c, "Error ", err.Error())
+		return
+	}
+
+	h.Helper.SendSuccess(c, "Success", trending)
+}
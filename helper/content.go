@@ -0,0 +1,67 @@
+package helper
+
+import (
+	"bytes"
+	"html"
+	"regexp"
+	"strings"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	goldmarkhtml "github.com/yuin/goldmark/renderer/html"
+)
+
+var (
+	// Raw HTML di markdown tetap dirender, keamanannya dijamin oleh sanitizer
+	markdownRenderer = goldmark.New(
+		goldmark.WithExtensions(extension.GFM),
+		goldmark.WithRendererOptions(goldmarkhtml.WithUnsafe()),
+	)
+
+	htmlSanitizer = newHTMLSanitizer()
+)
+
+// newHTMLSanitizer membuat policy untuk konten buatan user (tanpa script, event
+// handler, iframe, dll.), ditambah class language-* untuk syntax highlighting.
+func newHTMLSanitizer() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[\w+#-]+$`)).OnElements("code")
+	return p
+}
+
+// RenderMarkdown mengubah markdown (CommonMark + GFM) menjadi HTML yang sudah disanitasi.
+func RenderMarkdown(source string) (string, error) {
+	var buf bytes.Buffer
+	if err := markdownRenderer.Convert([]byte(source), &buf); err != nil {
+		return "", err
+	}
+	return SanitizeHTML(buf.String()), nil
+}
+
+// RenderPlainText meng-escape teks biasa; baris kosong memisahkan paragraf
+// dan baris baru di dalam paragraf menjadi <br>.
+func RenderPlainText(text string) string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+
+	var sb strings.Builder
+	for _, paragraph := range strings.Split(text, "\n\n") {
+		paragraph = strings.Trim(paragraph, "\n")
+		if strings.TrimSpace(paragraph) == "" {
+			continue
+		}
+		lines := strings.Split(paragraph, "\n")
+		for i := range lines {
+			lines[i] = html.EscapeString(lines[i])
+		}
+		sb.WriteString("<p>")
+		sb.WriteString(strings.Join(lines, "<br>\n"))
+		sb.WriteString("</p>\n")
+	}
+	return sb.String()
+}
+
+// SanitizeHTML membuang elemen dan atribut yang berpotensi XSS dari HTML.
+func SanitizeHTML(s string) string {
+	return htmlSanitizer.Sanitize(s)
+}
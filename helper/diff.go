@@ -0,0 +1,171 @@
+package helper
+
+import "unicode"
+
+// EditOp is the kind of change an Edit describes.
+type EditOp int
+
+const (
+	EditEqual EditOp = iota
+	EditDelete
+	EditInsert
+)
+
+// Edit is a single step of an edit script turning a into b.
+// OldIndex points into a (equal/delete), NewIndex points into b (equal/insert);
+// the index that does not apply is -1.
+type Edit struct {
+	Op       EditOp
+	OldIndex int
+	NewIndex int
+}
+
+// Diff computes the shortest edit script between a and b using Myers' algorithm.
+func Diff(a, b []string) []Edit {
+	// Strip common prefix and suffix, they never show up as changes
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	edits := make([]Edit, 0, len(a)+len(b))
+	for i := 0; i < prefix; i++ {
+		edits = append(edits, Edit{Op: EditEqual, OldIndex: i, NewIndex: i})
+	}
+
+	middle := myers(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])
+	for _, e := range middle {
+		if e.OldIndex >= 0 {
+			e.OldIndex += prefix
+		}
+		if e.NewIndex >= 0 {
+			e.NewIndex += prefix
+		}
+		edits = append(edits, e)
+	}
+
+	for i := 0; i < suffix; i++ {
+		edits = append(edits, Edit{
+			Op:       EditEqual,
+			OldIndex: len(a) - suffix + i,
+			NewIndex: len(b) - suffix + i,
+		})
+	}
+
+	return edits
+}
+
+func myers(a, b []string) []Edit {
+	n, m := len(a), len(b)
+	if n == 0 && m == 0 {
+		return nil
+	}
+
+	max := n + m
+	offset := max
+	v := make([]int, 2*max+2)
+
+	// trace[d] menyimpan snapshot v (diagonal -d..d) sebelum langkah ke-d
+	var trace [][]int
+
+search:
+	for d := 0; d <= max; d++ {
+		snapshot := make([]int, 2*d+1)
+		for k := -d; k <= d; k++ {
+			snapshot[k+d] = v[offset+k]
+		}
+		trace = append(trace, snapshot)
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				break search
+			}
+		}
+	}
+
+	// Backtrack dari (n, m) ke (0, 0)
+	var reversed []Edit
+	x, y := n, m
+	for d := len(trace) - 1; d > 0; d-- {
+		vd := trace[d]
+		k := x - y
+
+		var prevK int
+		if k == -d || (k != d && vd[k-1+d] < vd[k+1+d]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := vd[prevK+d]
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			x--
+			y--
+			reversed = append(reversed, Edit{Op: EditEqual, OldIndex: x, NewIndex: y})
+		}
+
+		if x == prevX {
+			y--
+			reversed = append(reversed, Edit{Op: EditInsert, OldIndex: -1, NewIndex: y})
+		} else {
+			x--
+			reversed = append(reversed, Edit{Op: EditDelete, OldIndex: x, NewIndex: -1})
+		}
+	}
+	for x > 0 && y > 0 {
+		x--
+		y--
+		reversed = append(reversed, Edit{Op: EditEqual, OldIndex: x, NewIndex: y})
+	}
+
+	edits := make([]Edit, len(reversed))
+	for i, e := range reversed {
+		edits[len(reversed)-1-i] = e
+	}
+	return edits
+}
+
+// SplitWords memecah teks menjadi token kata, whitespace dan tanda baca
+// sehingga hasil join-nya sama persis dengan teks aslinya.
+func SplitWords(s string) []string {
+	var tokens []string
+	runes := []rune(s)
+
+	class := func(r rune) int {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_':
+			return 1
+		case unicode.IsSpace(r):
+			return 2
+		default:
+			return 3
+		}
+	}
+
+	start := 0
+	for i := 1; i <= len(runes); i++ {
+		if i == len(runes) || class(runes[i]) != class(runes[start]) || class(runes[start]) == 3 {
+			tokens = append(tokens, string(runes[start:i]))
+			start = i
+		}
+	}
+
+	return tokens
+}
@@ -12,14 +12,16 @@ import (
 )
 
 const (
-	textError             = `error`
-	textOk                = `ok`
-	codeSuccess           = 200
-	codeBadRequestError   = 400
-	codeUnauthorizedError = 401
-	codeDatabaseError     = 402
-	codeValidationError   = 403
-	codeNotFound          = 404
+	textError                = `error`
+	textOk                   = `ok`
+	codeSuccess              = 200
+	codeBadRequestError      = 400
+	codeUnauthorizedError    = 401
+	codeDatabaseError        = 402
+	codeValidationError      = 403
+	codeNotFound             = 404
+	codePreconditionFailed   = 412
+	codePreconditionRequired = 428
 )
 
 // ResponseHelper ...
@@ -135,6 +137,22 @@ func (u *HTTPHelper) SendNotFoundErrorV2(c *gin.Context, message string, data in
 	return u.SendErrorV2(c, message, data, codeNotFound, `notFound`)
 }
 
+// SendPreconditionFailed ...
+// Send precondition failed response (HTTP 412) to consumers.
+func (u *HTTPHelper) SendPreconditionFailed(c *gin.Context, message string, data interface{}) error {
+	res := u.SetResponse(c, textError, message, data, codePreconditionFailed, `preconditionFailed`)
+
+	return u.SendResponseWithStatus(res, http.StatusPreconditionFailed)
+}
+
+// SendPreconditionRequired ...
+// Send precondition required response (HTTP 428) to consumers.
+func (u *HTTPHelper) SendPreconditionRequired(c *gin.Context, message string, data interface{}) error {
+	res := u.SetResponse(c, textError, message, data, codePreconditionRequired, `preconditionRequired`)
+
+	return u.SendResponseWithStatus(res, http.StatusPreconditionRequired)
+}
+
 // SendSuccess ...
 // Send success response to consumers.
 func (u *HTTPHelper) SendSuccess(c *gin.Context, message string, data interface{}) error {
@@ -185,6 +203,18 @@ func (u *HTTPHelper) SendResponseV2(res ResponseHelper) error {
 	return nil
 }
 
+// SendResponseWithStatus ...
+// Send response with an explicit HTTP status code.
+func (u *HTTPHelper) SendResponseWithStatus(res ResponseHelper, httpStatus int) error {
+	res.C.JSON(httpStatus, map[string]interface{}{
+		"code":         res.Code,
+		"code_type":    res.CodeType,
+		"code_message": res.Message,
+		"data":         res.Data,
+	})
+	return nil
+}
+
 func (u *HTTPHelper) EmptyJsonMap() map[string]interface{} {
 	return make(map[string]interface{})
 }
@@ -196,7 +226,12 @@ func (u *HTTPHelper) GetPagingUrl(c *gin.Context, page, limit int) string {
 	if c.Request.TLS != nil {
 		scheme = "https"
 	}
-	currentURL := scheme + "://" + r.Host + r.URL.Path + "?page=" + strconv.Itoa(page) + "&limit=" + strconv.Itoa(limit)
+	// Filter dan sort dari request dipertahankan, cursor diganti page
+	query := r.URL.Query()
+	query.Del("cursor")
+	query.Set("page", strconv.Itoa(page))
+	query.Set("limit", strconv.Itoa(limit))
+	currentURL := scheme + "://" + r.Host + r.URL.Path + "?" + query.Encode()
 	return currentURL
 }
 
@@ -0,0 +1,128 @@
+package helper
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// SlugMaxLength panjang maksimal slug sebelum suffix collision ditambahkan
+const SlugMaxLength = 80
+
+// Huruf yang tidak bisa diuraikan menjadi huruf latin dasar + diakritik
+var slugTransliterations = map[rune]string{
+	'ß': "ss", 'æ': "ae", 'œ': "oe", 'ø': "o", 'đ': "d", 'ð': "d", 'ł': "l", 'þ': "th", 'ı': "i",
+	'&': "and",
+	// Kiril
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e", 'ж': "zh", 'з': "z",
+	'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o", 'п': "p", 'р': "r",
+	'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "shch",
+	'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu", 'я': "ya", 'є': "ye", 'і': "i", 'ї': "yi", 'ґ': "g",
+	// Yunani
+	'α': "a", 'β': "v", 'γ': "g", 'δ': "d", 'ε': "e", 'ζ': "z", 'η': "i", 'θ': "th", 'ι': "i",
+	'κ': "k", 'λ': "l", 'μ': "m", 'ν': "n", 'ξ': "x", 'ο': "o", 'π': "p", 'ρ': "r", 'σ': "s",
+	'ς': "s", 'τ': "t", 'υ': "y", 'φ': "f", 'χ': "ch", 'ψ': "ps", 'ω': "o",
+}
+
+// Slugify mengubah judul menjadi slug URL: huruf latin ditransliterasi ke ASCII
+// (diakritik dibuang, Kiril/Yunani dialihaksarakan), huruf dari aksara lain
+// dipertahankan apa adanya, dan sisanya digabung dengan tanda hubung.
+func Slugify(s string) string {
+	// NFC dulu supaya huruf seperti й, ї dan ガ utuh satu rune saat dicari di
+	// tabel transliterasi
+	composed := norm.NFC.String(strings.ToLower(s))
+
+	var sb strings.Builder
+	pendingDash := false
+	// keepMarks: rune terakhir berasal dari aksara non-latin sehingga tanda
+	// gabung setelahnya (mis. vokal Devanagari) ikut dipertahankan
+	keepMarks := false
+	write := func(part string) {
+		if pendingDash && sb.Len() > 0 {
+			sb.WriteByte('-')
+		}
+		pendingDash = false
+		sb.WriteString(part)
+	}
+
+	for _, r := range composed {
+		if tr, ok := slugTransliterations[r]; ok {
+			if tr != "" {
+				write(tr)
+			}
+			keepMarks = false
+			continue
+		}
+		switch {
+		case unicode.Is(unicode.M, r):
+			if keepMarks && !pendingDash {
+				sb.WriteRune(r)
+			}
+		case unicode.Is(unicode.Latin, r) || !unicode.IsLetter(r):
+			// Hanya huruf latin (dan angka/simbol kompatibilitas seperti ²)
+			// yang diuraikan dan dibuang diakritiknya
+			keepMarks = false
+			for _, d := range stripMarks(r) {
+				if tr, ok := slugTransliterations[d]; ok {
+					if tr != "" {
+						write(tr)
+					}
+					continue
+				}
+				switch {
+				case unicode.IsLetter(d) || unicode.IsDigit(d):
+					write(string(d))
+				case d == '\'' || d == '’':
+					// "don't" menjadi "dont", bukan "don-t"
+				default:
+					pendingDash = true
+				}
+			}
+		default:
+			// Huruf Kiril/Yunani beraksen (mis. ά) memakai transliterasi huruf
+			// dasarnya; aksara lain (CJK, kana, Arab, dll.) tetap dipakai utuh
+			// termasuk diakritiknya, jadi ガ tidak berubah menjadi カ. Tanda
+			// dakuten setengah lebar (ﾞ, ﾟ) habis setelah diuraikan, jadi hasil
+			// kosong dipakai apa adanya.
+			if base := []rune(stripMarks(r)); len(base) > 0 {
+				if tr, ok := slugTransliterations[base[0]]; ok {
+					if tr != "" {
+						write(tr)
+					}
+					keepMarks = false
+					continue
+				}
+			}
+			write(string(r))
+			keepMarks = true
+		}
+	}
+
+	return truncateSlug(sb.String(), SlugMaxLength)
+}
+
+// stripMarks menguraikan r dengan NFKD dan membuang diakritiknya
+func stripMarks(r rune) string {
+	t := transform.Chain(norm.NFKD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	stripped, _, err := transform.String(t, string(r))
+	if err != nil {
+		return string(r)
+	}
+	return stripped
+}
+
+// truncateSlug memotong slug maksimal max rune, sebisa mungkin di batas kata.
+func truncateSlug(slug string, max int) string {
+	r := []rune(slug)
+	if len(r) <= max {
+		return slug
+	}
+	cut := string(r[:max])
+	if i := strings.LastIndexByte(cut, '-'); i > 0 {
+		cut = cut[:i]
+	}
+	return strings.Trim(cut, "-")
+}
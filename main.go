@@ -1,14 +1,17 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net/http"
 	"os"
+	"time"
 
 	"cisdi-test-cms/config"
 	"cisdi-test-cms/handlers"
 	"cisdi-test-cms/middleware"
 	"cisdi-test-cms/repositories"
+	"cisdi-test-cms/scheduler"
 	"cisdi-test-cms/services"
 
 	"github.com/gin-gonic/gin"
@@ -29,16 +32,62 @@ func main() {
 	articleRepo := repositories.NewArticleRepository(db)
 	tagRepo := repositories.NewTagRepository(db)
 	articleVersionRepo := repositories.NewArticleVersionRepository(db)
+	unitOfWork := repositories.NewUnitOfWork(db)
+	lockRepo := repositories.NewLockRepository(db)
 
 	// Initialize services
 	authService := services.NewAuthService(userRepo)
-	articleService := services.NewArticleService(articleRepo, tagRepo, articleVersionRepo)
-	tagService := services.NewTagService(tagRepo, articleRepo)
+	contentRenderer := services.NewContentRenderer(config.ContentRenderCacheSize())
+	tagNormalizer := services.NewTagNormalizer(config.TagNameMaxLength(), config.TagCaseFolding(), config.TagDisallowedChars())
+	relationshipScorer, err := services.NewRelationshipScorer(config.RelationshipScorer())
+	if err != nil {
+		log.Fatal(err)
+	}
+	sitemapService := services.NewSitemapService(articleRepo, tagRepo, services.SitemapMaxURLs, config.SitemapCacheTTL())
+	articleService := services.NewArticleService(articleRepo, tagRepo, articleVersionRepo, unitOfWork, contentRenderer, tagNormalizer, relationshipScorer, sitemapService)
+	tagService := services.NewTagService(tagRepo, articleRepo, tagNormalizer, relationshipScorer)
+	tagTrendingService := services.NewTagTrendingService(articleRepo, tagRepo, config.TagTrendingHalfLife())
+	feedService := services.NewFeedService(articleRepo, contentRenderer, config.FeedItemLimit())
+	relatedArticleService := services.NewRelatedArticleService(articleRepo, contentRenderer, config.RelatedArticleLimit(), config.RelatedArticleHalfLife())
+
+	// Background jobs (scheduled publish/unpublish, retensi trash, trending tag)
+	jobScheduler := scheduler.NewScheduler(lockRepo)
+	jobScheduler.Register(scheduler.Job{
+		Name:     "publish-scheduled-versions",
+		Interval: config.SchedulerInterval(),
+		Run:      articleService.RunScheduledPublishes,
+	})
+	jobScheduler.Register(scheduler.Job{
+		Name:     "unpublish-scheduled-versions",
+		Interval: config.SchedulerInterval(),
+		Run:      articleService.RunScheduledUnpublishes,
+	})
+	jobScheduler.Register(scheduler.Job{
+		Name:     "purge-trashed-articles",
+		Interval: config.TrashPurgeInterval(),
+		Run: func(now time.Time) error {
+			return articleService.PurgeTrashedBefore(now.Add(-config.TrashRetention()))
+		},
+	})
+	jobScheduler.Register(scheduler.Job{
+		Name:     "recompute-tag-trending",
+		Interval: config.TagTrendingInterval(),
+		Run: func(now time.Time) error {
+			if _, err := tagTrendingService.RecomputeTagUsage(now); err != nil {
+				return err
+			}
+			return tagTrendingService.PruneDailyUsage(now)
+		},
+	})
+	jobScheduler.Start(context.Background())
 
 	// Initialize handlers
 	authHandler := handlers.NewAuthHandler(authService)
 	articleHandler := handlers.NewArticleHandler(articleService)
-	tagHandler := handlers.NewTagHandler(tagService)
+	tagHandler := handlers.NewTagHandler(tagService, tagTrendingService)
+	feedHandler := handlers.NewFeedHandler(feedService)
+	relatedArticleHandler := handlers.NewRelatedArticleHandler(relatedArticleService)
+	sitemapHandler := handlers.NewSitemapHandler(sitemapService)
 
 	// Setup router
 	router := gin.Default()
@@ -46,8 +95,10 @@ func main() {
 	// CORS middleware
 	router.Use(func(c *gin.Context) {
 		c.Header("Access-Control-Allow-Origin", "*")
-		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Authorization")
+		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Authorization, If-Match, If-None-Match")
+		// ETag harus bisa dibaca client browser untuk dikirim balik sebagai If-Match
+		c.Header("Access-Control-Expose-Headers", "ETag, Last-Modified")
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
@@ -62,6 +113,10 @@ func main() {
 		c.JSON(http.StatusOK, gin.H{"status": "healthy"})
 	})
 
+	// Sitemap
+	router.GET("/sitemap.xml", sitemapHandler.GetSitemap)
+	router.GET("/sitemaps/:name", sitemapHandler.GetSitemapPage)
+
 	// API routes
 	v1 := router.Group("/api/v1")
 	{
@@ -84,20 +139,46 @@ func main() {
 			{
 				articles.POST("", articleHandler.CreateArticle)
 				articles.GET("", articleHandler.GetArticles)
+				articles.GET("/schedules", articleHandler.GetPendingSchedules)
+				articles.POST("/score-preview", articleHandler.PreviewScore)
 				articles.GET("/:id", articleHandler.GetArticle)
+				articles.PATCH("/:id", articleHandler.UpdateArticle)
 				articles.DELETE("/:id", articleHandler.DeleteArticle)
+				articles.GET("/:id/audits", articleHandler.GetArticleAudits)
 				articles.POST("/:id/versions", articleHandler.CreateArticleVersion)
 				articles.PUT("/:id/versions/:version_id/status", articleHandler.UpdateVersionStatus)
 				articles.GET("/:id/versions", articleHandler.GetArticleVersions)
 				articles.GET("/:id/versions/:version_id", articleHandler.GetArticleVersion)
+				articles.GET("/:id/versions/:version_id/diff", articleHandler.DiffArticleVersions)
+				articles.POST("/:id/versions/:version_id/revert", articleHandler.RevertArticleVersion)
+				articles.PUT("/:id/versions/:version_id/schedule", articleHandler.ScheduleVersion)
+				articles.DELETE("/:id/versions/:version_id/schedule", articleHandler.CancelVersionSchedule)
+				articles.GET("/:id/versions/:version_id/reviews", articleHandler.GetVersionReviews)
+			}
+
+			trash := protected.Group("/admin/trash/articles")
+			{
+				trash.GET("", articleHandler.GetTrashedArticles)
+				trash.POST("/:id/restore", articleHandler.RestoreArticle)
+				trash.DELETE("/:id/purge", articleHandler.PurgeArticle)
 			}
 
 			// Tags
+			protected.POST("/admin/tags/recompute-trending", tagHandler.RecomputeTrending)
+
 			tags := protected.Group("/tags")
 			{
 				tags.POST("", tagHandler.CreateTag)
 				tags.GET("", tagHandler.GetTags)
+				tags.GET("/tree", tagHandler.GetTagTree)
+				tags.POST("/suggest", tagHandler.SuggestTags)
 				tags.GET("/:id", tagHandler.GetTag)
+				tags.PUT("/:id", tagHandler.RenameTag)
+				tags.DELETE("/:id", tagHandler.DeleteTag)
+				tags.POST("/:id/merge", tagHandler.MergeTag)
+				tags.PUT("/:id/parent", tagHandler.SetTagParent)
+				tags.POST("/:id/aliases", tagHandler.AddTagAlias)
+				tags.DELETE("/:id/aliases/:alias_id", tagHandler.RemoveTagAlias)
 			}
 		}
 
@@ -105,7 +186,12 @@ func main() {
 		public := v1.Group("/public")
 		{
 			public.GET("/articles", articleHandler.GetPublicArticles)
+			public.GET("/articles/by-slug/:slug", articleHandler.GetPublicArticleBySlug)
 			public.GET("/articles/:id", articleHandler.GetPublicArticle)
+			public.GET("/articles/:id/related", relatedArticleHandler.GetRelatedArticles)
+			public.GET("/tags/trending", tagHandler.GetTrendingTags)
+			public.GET("/feed.rss", feedHandler.GetRSSFeed)
+			public.GET("/feed.atom", feedHandler.GetAtomFeed)
 		}
 	}
 
@@ -0,0 +1,4 @@
+-- Upgrade untuk database yang sudah berjalan sebelum fitur revert versi.
+-- Instalasi baru cukup memakai init.sql.
+ALTER TABLE article_versions
+  ADD COLUMN IF NOT EXISTS reverted_from_version_id INTEGER REFERENCES article_versions(id);
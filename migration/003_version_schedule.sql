@@ -0,0 +1,7 @@
+-- Upgrade untuk penjadwalan publish/unpublish versi artikel.
+ALTER TABLE article_versions
+  ADD COLUMN IF NOT EXISTS publish_at TIMESTAMP,
+  ADD COLUMN IF NOT EXISTS unpublish_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_article_versions_publish_at ON article_versions (publish_at) WHERE publish_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_article_versions_unpublish_at ON article_versions (unpublish_at) WHERE unpublish_at IS NOT NULL;
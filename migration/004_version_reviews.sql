@@ -0,0 +1,11 @@
+-- Upgrade untuk workflow review versi (in_review, changes_requested, approved).
+CREATE TABLE IF NOT EXISTS version_reviews (
+  id SERIAL PRIMARY KEY,
+  article_version_id INTEGER NOT NULL REFERENCES article_versions(id) ON DELETE CASCADE,
+  reviewer_id INTEGER NOT NULL REFERENCES users(id),
+  from_status VARCHAR(50),
+  to_status VARCHAR(50),
+  comment TEXT,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_version_reviews_article_version_id ON version_reviews (article_version_id);
@@ -0,0 +1,9 @@
+-- Upgrade untuk full-text search artikel (parameter q pada /articles dan /public/articles).
+-- Kolom generated otomatis terisi untuk semua versi yang sudah ada.
+ALTER TABLE article_versions
+  ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', coalesce(title, '')), 'A') ||
+    setweight(to_tsvector('simple', coalesce(content, '')), 'B')
+  ) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_article_versions_search_vector ON article_versions USING GIN (search_vector);
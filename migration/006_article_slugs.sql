@@ -0,0 +1,19 @@
+-- Upgrade untuk slug artikel (/public/articles/by-slug/:slug).
+-- Artikel lama diberi slug sederhana dari judul + ID agar dijamin unik;
+-- slug baru dengan transliterasi lengkap dibuat aplikasi saat versi berikutnya dipublikasikan.
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS slug VARCHAR(255);
+
+UPDATE articles
+SET slug = coalesce(nullif(trim(both '-' from regexp_replace(lower(title), '[^a-z0-9]+', '-', 'g')), ''), 'article') || '-' || id
+WHERE slug IS NULL;
+
+ALTER TABLE articles ALTER COLUMN slug SET NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_articles_slug ON articles (slug);
+
+CREATE TABLE IF NOT EXISTS article_slugs (
+  id SERIAL PRIMARY KEY,
+  article_id INTEGER NOT NULL REFERENCES articles(id) ON DELETE CASCADE,
+  slug VARCHAR(255) UNIQUE NOT NULL,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_article_slugs_article_id ON article_slugs (article_id);
@@ -0,0 +1,3 @@
+-- Upgrade untuk format konten versi artikel (markdown, html, plain).
+-- Konten lama dianggap HTML, sesuai perilaku sebelumnya.
+ALTER TABLE article_versions ADD COLUMN IF NOT EXISTS content_format VARCHAR(20) NOT NULL DEFAULT 'html';
@@ -0,0 +1,14 @@
+-- Upgrade untuk constraint unique (article_id, version_number).
+-- Data lama bisa berisi nomor versi ganda akibat request bersamaan; versi
+-- dinomori ulang per artikel berdasarkan urutan nomor lama lalu ID.
+UPDATE article_versions av
+SET version_number = renumbered.rn
+FROM (
+  SELECT id, ROW_NUMBER() OVER (PARTITION BY article_id ORDER BY version_number, id) AS rn
+  FROM article_versions
+) renumbered
+WHERE av.id = renumbered.id
+  AND av.version_number <> renumbered.rn;
+
+ALTER TABLE article_versions
+  ADD CONSTRAINT unique_article_version_number UNIQUE (article_id, version_number);
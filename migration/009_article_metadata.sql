@@ -0,0 +1,20 @@
+-- Upgrade untuk PATCH /articles/:id (featured flag dan audit metadata artikel).
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS featured BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE IF NOT EXISTS article_audits (
+  id SERIAL PRIMARY KEY,
+  article_id INTEGER NOT NULL REFERENCES articles(id) ON DELETE CASCADE,
+  actor_id INTEGER REFERENCES users(id),
+  field VARCHAR(50) NOT NULL,
+  old_value TEXT,
+  new_value TEXT,
+  source VARCHAR(20) NOT NULL,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_article_audits_article_id ON article_audits (article_id);
+
+-- Judul artikel yang sudah published disamakan dengan versi yang dipublikasikan
+UPDATE articles a
+SET title = av.title
+FROM article_versions av
+WHERE av.id = a.published_version_id AND a.title <> av.title;
@@ -0,0 +1,8 @@
+-- Upgrade untuk manajemen tag (rename, merge, alias).
+CREATE TABLE IF NOT EXISTS tag_aliases (
+  id SERIAL PRIMARY KEY,
+  tag_id INTEGER NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+  alias VARCHAR(255) UNIQUE NOT NULL,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_tag_aliases_tag_id ON tag_aliases (tag_id);
@@ -0,0 +1,3 @@
+-- Upgrade untuk hierarki tag (GET /tags/tree, filter include_descendants).
+ALTER TABLE tags ADD COLUMN IF NOT EXISTS parent_id INTEGER REFERENCES tags(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_tags_parent_id ON tags (parent_id);
@@ -0,0 +1,37 @@
+-- Upgrade untuk statistik tag yang sudah dihitung (tag_stats, tag_pair_stats).
+CREATE TABLE IF NOT EXISTS tag_stats (
+  tag_id INTEGER PRIMARY KEY REFERENCES tags(id) ON DELETE CASCADE,
+  article_count INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE TABLE IF NOT EXISTS tag_pair_stats (
+  tag_id_a INTEGER NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+  tag_id_b INTEGER NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+  article_count INTEGER NOT NULL DEFAULT 0,
+  PRIMARY KEY (tag_id_a, tag_id_b),
+  CHECK (tag_id_a < tag_id_b)
+);
+CREATE INDEX IF NOT EXISTS idx_tag_pair_stats_tag_id_b ON tag_pair_stats (tag_id_b);
+
+-- Isi awal dari data yang sudah ada (sama dengan go run ./cmd/rebuild-tag-stats)
+DELETE FROM tag_pair_stats;
+DELETE FROM tag_stats;
+
+INSERT INTO tag_stats (tag_id, article_count)
+SELECT avt.tag_id, COUNT(DISTINCT a.id)
+FROM articles a
+JOIN article_versions av ON av.id = a.latest_version_id
+JOIN article_version_tags avt ON avt.article_version_id = av.id
+WHERE a.deleted_at IS NULL
+  AND av.deleted_at IS NULL
+GROUP BY avt.tag_id;
+
+INSERT INTO tag_pair_stats (tag_id_a, tag_id_b, article_count)
+SELECT avt1.tag_id, avt2.tag_id, COUNT(DISTINCT a.id)
+FROM articles a
+JOIN article_versions av ON av.id = a.latest_version_id
+JOIN article_version_tags avt1 ON avt1.article_version_id = av.id
+JOIN article_version_tags avt2 ON avt2.article_version_id = av.id AND avt1.tag_id < avt2.tag_id
+WHERE a.deleted_at IS NULL
+  AND av.deleted_at IS NULL
+GROUP BY avt1.tag_id, avt2.tag_id;
@@ -0,0 +1,17 @@
+-- Upgrade untuk job recompute-tag-trending: decay trending_score memakai
+-- last_used_at, bukan updated_at.
+ALTER TABLE tags ADD COLUMN IF NOT EXISTS last_used_at TIMESTAMP NULL;
+
+UPDATE tags t
+SET last_used_at = u.last_used_at
+FROM (
+  SELECT avt.tag_id, MAX(av.published_at) AS last_used_at
+  FROM article_version_tags avt
+  JOIN article_versions av ON av.id = avt.article_version_id
+  JOIN articles a ON a.id = av.article_id
+  WHERE av.status = 'published'
+    AND av.deleted_at IS NULL
+    AND a.deleted_at IS NULL
+  GROUP BY avt.tag_id
+) u
+WHERE u.tag_id = t.id AND t.last_used_at IS NULL;
@@ -0,0 +1,21 @@
+-- Upgrade untuk GET /public/tags/trending (bucket publish harian per tag).
+CREATE TABLE IF NOT EXISTS tag_daily_usage (
+  tag_id INTEGER NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+  day DATE NOT NULL,
+  publish_count INTEGER NOT NULL DEFAULT 0,
+  PRIMARY KEY (tag_id, day)
+);
+CREATE INDEX IF NOT EXISTS idx_tag_daily_usage_day ON tag_daily_usage (day);
+
+-- Isi awal dari versi yang saat ini published; publish sebelumnya tidak tercatat
+INSERT INTO tag_daily_usage (tag_id, day, publish_count)
+SELECT avt.tag_id, av.published_at::date, COUNT(*)
+FROM article_version_tags avt
+JOIN article_versions av ON av.id = avt.article_version_id
+JOIN articles a ON a.id = av.article_id
+WHERE av.status = 'published'
+  AND av.published_at >= CURRENT_DATE - INTERVAL '60 days'
+  AND av.deleted_at IS NULL
+  AND a.deleted_at IS NULL
+GROUP BY avt.tag_id, av.published_at::date
+ON CONFLICT (tag_id, day) DO NOTHING;
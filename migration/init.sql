@@ -16,6 +16,8 @@ CREATE TABLE articles (
   id SERIAL PRIMARY KEY,
   author_id INTEGER NOT NULL REFERENCES users(id),
   title VARCHAR(255) NOT NULL,
+  slug VARCHAR(255) UNIQUE NOT NULL,
+  featured BOOLEAN NOT NULL DEFAULT FALSE,
   published_version_id INTEGER,
   latest_version_id INTEGER NOT NULL,
   created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
@@ -23,6 +25,28 @@ CREATE TABLE articles (
   deleted_at TIMESTAMP NULL
 );
 
+-- Riwayat slug artikel, slug lama di-redirect ke slug terbaru
+CREATE TABLE article_slugs (
+  id SERIAL PRIMARY KEY,
+  article_id INTEGER NOT NULL REFERENCES articles(id) ON DELETE CASCADE,
+  slug VARCHAR(255) UNIQUE NOT NULL,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_article_slugs_article_id ON article_slugs (article_id);
+
+-- Audit perubahan metadata artikel (title, author, featured)
+CREATE TABLE article_audits (
+  id SERIAL PRIMARY KEY,
+  article_id INTEGER NOT NULL REFERENCES articles(id) ON DELETE CASCADE,
+  actor_id INTEGER REFERENCES users(id),
+  field VARCHAR(50) NOT NULL,
+  old_value TEXT,
+  new_value TEXT,
+  source VARCHAR(20) NOT NULL,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_article_audits_article_id ON article_audits (article_id);
+
 -- Tabel Article Versions
 CREATE TABLE article_versions (
   id SERIAL PRIMARY KEY,
@@ -30,25 +54,66 @@ CREATE TABLE article_versions (
   version_number INTEGER NOT NULL,
   title VARCHAR(255) NOT NULL,
   content TEXT,
+  content_format VARCHAR(20) NOT NULL DEFAULT 'html', -- markdown, html, plain
   status VARCHAR(50) DEFAULT 'draft',
   article_tag_relationship_score DECIMAL(6,2) DEFAULT 0,
+  reverted_from_version_id INTEGER REFERENCES article_versions(id),
   published_at TIMESTAMP,
+  publish_at TIMESTAMP,
+  unpublish_at TIMESTAMP,
+  -- Full-text search, judul diberi bobot lebih tinggi dari konten
+  search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', coalesce(title, '')), 'A') ||
+    setweight(to_tsvector('simple', coalesce(content, '')), 'B')
+  ) STORED,
   created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
   updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-  deleted_at TIMESTAMP NULL
+  deleted_at TIMESTAMP NULL,
+  -- Nomor versi unik per artikel, request bersamaan yang bentrok akan di-retry
+  CONSTRAINT unique_article_version_number UNIQUE (article_id, version_number)
 );
 
+CREATE INDEX idx_article_versions_search_vector ON article_versions USING GIN (search_vector);
+
+-- Index untuk job penjadwalan publish/unpublish
+CREATE INDEX idx_article_versions_publish_at ON article_versions (publish_at) WHERE publish_at IS NOT NULL;
+CREATE INDEX idx_article_versions_unpublish_at ON article_versions (unpublish_at) WHERE unpublish_at IS NOT NULL;
+
+-- Riwayat workflow review versi artikel
+CREATE TABLE version_reviews (
+  id SERIAL PRIMARY KEY,
+  article_version_id INTEGER NOT NULL REFERENCES article_versions(id) ON DELETE CASCADE,
+  reviewer_id INTEGER NOT NULL REFERENCES users(id),
+  from_status VARCHAR(50),
+  to_status VARCHAR(50),
+  comment TEXT,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_version_reviews_article_version_id ON version_reviews (article_version_id);
+
 -- Tabel Tags
 CREATE TABLE tags (
   id SERIAL PRIMARY KEY,
   name VARCHAR(255) UNIQUE NOT NULL,
+  parent_id INTEGER REFERENCES tags(id) ON DELETE SET NULL,
   usage_count INTEGER DEFAULT 0, -- perlu dijaga konsistensinya dengan trigger
   trending_score DECIMAL(10,6) DEFAULT 0,
+  last_used_at TIMESTAMP NULL, -- publish terbaru yang memakai tag, basis decay trending_score
   created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
   updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
   deleted_at TIMESTAMP NULL
 );
 
+-- Alias tag, diarahkan ke tag kanonik saat tag versi diproses
+CREATE TABLE tag_aliases (
+  id SERIAL PRIMARY KEY,
+  tag_id INTEGER NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+  alias VARCHAR(255) UNIQUE NOT NULL,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_tag_aliases_tag_id ON tag_aliases (tag_id);
+CREATE INDEX idx_tags_parent_id ON tags (parent_id);
+
 -- Many-to-many relationship
 CREATE TABLE article_version_tags (
   id SERIAL PRIMARY KEY,
@@ -61,3 +126,27 @@ CREATE TABLE article_version_tags (
     FOREIGN KEY (tag_id) REFERENCES tags(id) ON DELETE CASCADE,
   CONSTRAINT unique_article_version_tag UNIQUE (article_version_id, tag_id)
 );
+
+-- Statistik tag untuk PMI, di-update bertahap dari latest version artikel
+CREATE TABLE tag_stats (
+  tag_id INTEGER PRIMARY KEY REFERENCES tags(id) ON DELETE CASCADE,
+  article_count INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE TABLE tag_pair_stats (
+  tag_id_a INTEGER NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+  tag_id_b INTEGER NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+  article_count INTEGER NOT NULL DEFAULT 0,
+  PRIMARY KEY (tag_id_a, tag_id_b),
+  CHECK (tag_id_a < tag_id_b)
+);
+CREATE INDEX idx_tag_pair_stats_tag_id_b ON tag_pair_stats (tag_id_b);
+
+-- Jumlah publish per tag per hari (UTC) untuk trending per window
+CREATE TABLE tag_daily_usage (
+  tag_id INTEGER NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+  day DATE NOT NULL,
+  publish_count INTEGER NOT NULL DEFAULT 0,
+  PRIMARY KEY (tag_id, day)
+);
+CREATE INDEX idx_tag_daily_usage_day ON tag_daily_usage (day);
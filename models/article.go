@@ -11,11 +11,16 @@ type Article struct {
 	AuthorID           uint             `json:"author_id" gorm:"not null"`
 	Author             User             `json:"author" gorm:"foreignKey:AuthorID"`
 	Title              string           `json:"title" gorm:"not null"`
+	Slug               string           `json:"slug" gorm:"uniqueIndex;not null"`
+	Featured           bool             `json:"featured" gorm:"not null;default:false"`
 	PublishedVersionID *uint            `json:"published_version_id"`
 	PublishedVersion   *ArticleVersion  `json:"published_version,omitempty" gorm:"foreignKey:PublishedVersionID"`
 	LatestVersionID    uint             `json:"latest_version_id"`
 	LatestVersion      ArticleVersion   `json:"latest_version" gorm:"foreignKey:LatestVersionID"`
 	Versions           []ArticleVersion `json:"versions,omitempty" gorm:"foreignKey:ArticleID"`
+	SearchRank         float64          `json:"search_rank,omitempty" gorm:"->;-:migration"`
+	Snippet            string           `json:"snippet,omitempty" gorm:"->;-:migration"`
+	SortKey            string           `json:"-" gorm:"->;-:migration"` // nilai kolom sort_by untuk next_cursor
 	CreatedAt          time.Time        `json:"created_at"`
 	UpdatedAt          time.Time        `json:"updated_at"`
 	DeletedAt          gorm.DeletedAt   `json:"-" gorm:"index"`
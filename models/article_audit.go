@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// Sumber perubahan metadata artikel yang dicatat di ArticleAudit
+const (
+	AuditSourcePatch   = "patch"   // PATCH /articles/:id
+	AuditSourcePublish = "publish" // judul mengikuti versi yang dipublikasikan
+)
+
+// ArticleAudit mencatat perubahan satu field metadata artikel (title, author,
+// featured) beserta pelakunya. ActorID kosong untuk perubahan oleh scheduler.
+type ArticleAudit struct {
+	ID        uint      `json:"id" gorm:"primarykey"`
+	ArticleID uint      `json:"article_id" gorm:"not null;index"`
+	ActorID   *uint     `json:"actor_id"`
+	Actor     *User     `json:"actor,omitempty" gorm:"foreignKey:ActorID"`
+	Field     string    `json:"field" gorm:"not null"`
+	OldValue  string    `json:"old_value"`
+	NewValue  string    `json:"new_value"`
+	Source    string    `json:"source" gorm:"not null"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// ArticleSlug menyimpan slug lama sebuah artikel agar URL lama tetap bisa
+// di-redirect ke slug terbaru setelah judul berubah.
+type ArticleSlug struct {
+	ID        uint      `json:"id" gorm:"primarykey"`
+	ArticleID uint      `json:"article_id" gorm:"not null;index"`
+	Slug      string    `json:"slug" gorm:"uniqueIndex;not null"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -9,22 +9,38 @@ import (
 type VersionStatus string
 
 const (
-	StatusDraft           VersionStatus = "draft"
-	StatusPublished       VersionStatus = "published"
-	StatusArchivedVersion VersionStatus = "archived_version"
+	StatusDraft            VersionStatus = "draft"
+	StatusInReview         VersionStatus = "in_review"
+	StatusChangesRequested VersionStatus = "changes_requested"
+	StatusApproved         VersionStatus = "approved"
+	StatusPublished        VersionStatus = "published"
+	StatusArchivedVersion  VersionStatus = "archived_version"
+)
+
+type ContentFormat string
+
+const (
+	ContentFormatMarkdown ContentFormat = "markdown"
+	ContentFormatHTML     ContentFormat = "html"
+	ContentFormatPlain    ContentFormat = "plain"
 )
 
 type ArticleVersion struct {
 	ID                          uint           `json:"id" gorm:"primarykey"`
-	ArticleID                   uint           `json:"article_id" gorm:"not null"`
+	ArticleID                   uint           `json:"article_id" gorm:"not null;uniqueIndex:unique_article_version_number,priority:1"`
 	Article                     *Article       `json:"article,omitempty" gorm:"foreignKey:ArticleID"`
-	VersionNumber               int            `json:"version_number" gorm:"not null"`
+	VersionNumber               int            `json:"version_number" gorm:"not null;uniqueIndex:unique_article_version_number,priority:2"`
 	Title                       string         `json:"title" gorm:"not null"`
 	Content                     string         `json:"content" gorm:"type:text"`
+	ContentFormat               ContentFormat  `json:"content_format" gorm:"default:'html'"`
+	ContentHTML                 string         `json:"content_html,omitempty" gorm:"-"`
 	Status                      VersionStatus  `json:"status" gorm:"default:'draft'"`
 	ArticleTagRelationshipScore float64        `json:"article_tag_relationship_score" gorm:"default:0"`
 	Tags                        []Tag          `json:"tags" gorm:"many2many:article_version_tags;"`
+	RevertedFromVersionID       *uint          `json:"reverted_from_version_id"`
 	PublishedAt                 *time.Time     `json:"published_at"`
+	PublishAt                   *time.Time     `json:"publish_at"`
+	UnpublishAt                 *time.Time     `json:"unpublish_at"`
 	CreatedAt                   time.Time      `json:"created_at"`
 	UpdatedAt                   time.Time      `json:"updated_at"`
 	DeletedAt                   gorm.DeletedAt `json:"-" gorm:"index"`
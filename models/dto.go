@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 type RegisterRequest struct {
 	Username string   `json:"username" binding:"required,min=3,max=50"`
 	Email    string   `json:"email" binding:"required,email"`
@@ -18,31 +20,115 @@ type AuthResponse struct {
 }
 
 type CreateArticleRequest struct {
-	Title   string   `json:"title" binding:"required,min=1,max=255"`
-	Content string   `json:"content" binding:"required"`
-	Tags    []string `json:"tags"`
+	Title         string        `json:"title" binding:"required,min=1,max=255"`
+	Content       string        `json:"content" binding:"required"`
+	ContentFormat ContentFormat `json:"content_format" binding:"omitempty,oneof=markdown html plain"`
+	Tags          []string      `json:"tags"`
 }
 
+// CreateArticleVersionRequest membutuhkan header If-Match atau BaseVersionID
+// (latest version yang menjadi dasar perubahan) untuk optimistic concurrency.
 type CreateArticleVersionRequest struct {
-	Title   string   `json:"title" binding:"required,min=1,max=255"`
-	Content string   `json:"content" binding:"required"`
-	Tags    []string `json:"tags"`
+	Title         string        `json:"title" binding:"required,min=1,max=255"`
+	Content       string        `json:"content" binding:"required"`
+	ContentFormat ContentFormat `json:"content_format" binding:"omitempty,oneof=markdown html plain"`
+	Tags          []string      `json:"tags"`
+	BaseVersionID *uint         `json:"base_version_id"`
+	IfMatch       string        `json:"-"`
+}
+
+// RevertArticleVersionRequest memakai precondition yang sama dengan
+// CreateArticleVersionRequest: header If-Match atau BaseVersionID wajib ada.
+type RevertArticleVersionRequest struct {
+	BaseVersionID *uint  `json:"base_version_id"`
+	IfMatch       string `json:"-"`
+}
+
+// UpdateArticleRequest berisi metadata artikel untuk PATCH; field yang tidak
+// dikirim tidak diubah. Konten tetap diubah melalui versi baru.
+type UpdateArticleRequest struct {
+	Title    *string `json:"title" binding:"omitempty,min=1,max=255"`
+	AuthorID *uint   `json:"author_id"`
+	Featured *bool   `json:"featured"`
+	IfMatch  string  `json:"-"`
 }
 
 type UpdateVersionStatusRequest struct {
-	Status VersionStatus `json:"status" binding:"required"`
+	Status        VersionStatus `json:"status" binding:"required"`
+	Comment       string        `json:"comment" binding:"max=2000"`
+	BaseVersionID *uint         `json:"base_version_id"`
+	IfMatch       string        `json:"-"`
+}
+
+type ScheduleVersionRequest struct {
+	PublishAt   *time.Time `json:"publish_at"`
+	UnpublishAt *time.Time `json:"unpublish_at"`
 }
 
 type CreateTagRequest struct {
+	Name     string `json:"name" binding:"required,min=1,max=100"`
+	ParentID *uint  `json:"parent_id"`
+}
+
+// SetTagParentRequest memindahkan tag di hierarki; ParentID null menjadikannya tag root
+type SetTagParentRequest struct {
+	ParentID *uint `json:"parent_id"`
+}
+
+type RenameTagRequest struct {
 	Name string `json:"name" binding:"required,min=1,max=100"`
 }
 
+// MergeTagRequest menggabungkan tag pada URL ke TargetID
+type MergeTagRequest struct {
+	TargetID uint `json:"target_id" binding:"required"`
+}
+
+type CreateTagAliasRequest struct {
+	Alias string `json:"alias" binding:"required,min=1,max=100"`
+}
+
+// SuggestTagsRequest berisi tag yang sudah dipilih penulis dan/atau isi draft.
+// Minimal salah satu harus diisi.
+type SuggestTagsRequest struct {
+	Tags    []string `json:"tags" binding:"max=50"`
+	Content string   `json:"content"`
+	Limit   int      `json:"limit" binding:"omitempty,min=1,max=50"`
+}
+
+// ScoreTagsRequest berisi daftar tag kandidat untuk POST /articles/score-preview
+type ScoreTagsRequest struct {
+	Tags []string `json:"tags" binding:"required,max=50"`
+}
+
+// TrendingTagParams adalah query GET /public/tags/trending
+type TrendingTagParams struct {
+	Window string `form:"window,default=7d" binding:"oneof=24h 7d 30d"`
+	Limit  int    `form:"limit,default=10" binding:"min=1,max=100"`
+	Sort   string `form:"sort,default=count" binding:"oneof=count velocity"`
+}
+
 type ArticleListParams struct {
-	Status    string `form:"status"`
-	AuthorID  uint   `form:"author_id"`
-	TagID     uint   `form:"tag_id"`
-	Page      int    `form:"page,default=1"`
-	Limit     int    `form:"limit,default=10"`
-	SortBy    string `form:"sort_by,default=created_at"`
-	SortOrder string `form:"sort_order,default=desc"`
+	Query              string         `form:"q"`
+	Status             string         `form:"status"`
+	AuthorID           uint           `form:"author_id"`
+	TagID              uint           `form:"tag_id"`
+	IncludeDescendants bool           `form:"include_descendants"` // TagID ikut mencocokkan tag turunannya
+	TagIDs             []uint         `form:"-"`                   // diisi service dari TagID + turunannya
+	Page               int            `form:"page,default=1"`
+	Limit              int            `form:"limit,default=10"`
+	SortBy             string         `form:"sort_by,default=created_at"`
+	SortOrder          string         `form:"sort_order,default=desc"`
+	Cursor             string         `form:"cursor"` // next_cursor dari halaman sebelumnya, menggantikan page
+	After              *ArticleCursor `form:"-"`      // diisi service dari Cursor
+}
+
+// ArticleCursor adalah isi cursor keyset pagination: posisi artikel terakhir
+// di halaman sebelumnya pada urutan SortBy/SortOrder. Value adalah nilai kolom
+// sort dalam bentuk teks (lihat Article.SortKey).
+type ArticleCursor struct {
+	SortBy    string `json:"s"`
+	SortOrder string `json:"o"`
+	Value     string `json:"v"`
+	ID        uint   `json:"id"`
 }
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+type FeedParams struct {
+	TagID    uint `form:"tag_id"`
+	AuthorID uint `form:"author_id"`
+	Limit    int  `form:"limit" binding:"omitempty,min=1"`
+}
+
+type FeedItem struct {
+	ArticleID   uint
+	Slug        string
+	Title       string
+	Summary     string
+	Content     string
+	AuthorName  string
+	Tags        []string
+	PublishedAt time.Time
+	UpdatedAt   time.Time
+}
+
+// Feed berisi artikel published terbaru untuk dirender sebagai RSS atau Atom.
+// Updated adalah waktu perubahan terakhir dari seluruh item, dipakai untuk
+// Last-Modified, sedangkan Version adalah hash isi feed untuk ETag.
+type Feed struct {
+	Items   []FeedItem
+	Updated time.Time
+	Version string
+}
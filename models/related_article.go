@@ -0,0 +1,9 @@
+package models
+
+// RelatedArticle adalah artikel published yang mirip dengan artikel lain
+// berdasarkan tag. MatchedTags adalah tag yang ikut menyumbang skor.
+type RelatedArticle struct {
+	Article
+	Score       float64  `json:"score"`
+	MatchedTags []string `json:"matched_tags"`
+}
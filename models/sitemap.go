@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// SitemapEntry adalah satu URL di sitemap. Path relatif terhadap base URL
+// publik, base URL ditambahkan saat response dirender.
+type SitemapEntry struct {
+	Path    string
+	LastMod time.Time
+}
+
+// Sitemap berisi seluruh URL publik yang sudah dibagi per file sitemap.
+type Sitemap struct {
+	Pages       [][]SitemapEntry
+	GeneratedAt time.Time
+}
+
+type SitemapArticle struct {
+	ID      uint
+	Slug    string
+	LastMod time.Time
+}
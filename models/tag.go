@@ -9,9 +9,95 @@ import (
 type Tag struct {
 	ID            uint           `json:"id" gorm:"primarykey"`
 	Name          string         `json:"name" gorm:"uniqueIndex;not null"`
+	ParentID      *uint          `json:"parent_id" gorm:"index"`
 	UsageCount    int            `json:"usage_count" gorm:"default:0"`
 	TrendingScore float64        `json:"trending_score" gorm:"default:0"`
+	LastUsedAt    *time.Time     `json:"last_used_at"`
+	Aliases       []TagAlias     `json:"aliases,omitempty" gorm:"foreignKey:TagID"`
 	CreatedAt     time.Time      `json:"created_at"`
 	UpdatedAt     time.Time      `json:"updated_at"`
 	DeletedAt     gorm.DeletedAt `json:"-" gorm:"index"`
 }
+
+// TagAlias adalah nama alternatif (mis. "golang") yang otomatis diarahkan ke
+// tag kanonik (mis. "go") saat tag versi diproses. Alias tidak boleh sama
+// dengan nama tag lain.
+type TagAlias struct {
+	ID        uint      `json:"id" gorm:"primarykey"`
+	TagID     uint      `json:"tag_id" gorm:"not null;index"`
+	Alias     string    `json:"alias" gorm:"uniqueIndex;not null"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TagDuplicateGroup adalah sekumpulan tag yang namanya sama setelah
+// dinormalisasi. Duplicates digabung ke Canonical, lalu Canonical diberi nama Name.
+type TagDuplicateGroup struct {
+	Name       string `json:"name"`
+	Canonical  Tag    `json:"canonical"`
+	Duplicates []Tag  `json:"duplicates"`
+}
+
+// TagTreeNode adalah tag beserta turunannya untuk GET /tags/tree
+type TagTreeNode struct {
+	Tag
+	Children []TagTreeNode `json:"children"`
+}
+
+// TagSuggestion adalah kandidat tag untuk draft. Score adalah rata-rata PMI
+// terhadap tag input yang pernah muncul bersamanya; Mentioned berarti nama tag
+// ditemukan di isi draft.
+type TagSuggestion struct {
+	TagID         uint    `json:"tag_id"`
+	Name          string  `json:"name"`
+	Score         float64 `json:"score"`
+	CoOccurrences int     `json:"co_occurrences"`
+	Mentioned     bool    `json:"mentioned"`
+}
+
+// TagRelationshipScore adalah hasil preview article_tag_relationship_score
+// untuk sekumpulan tag tanpa menyimpan apapun. Tags berisi nama tag setelah
+// normalisasi dan alias; Score adalah rata-rata skor pasangan yang Counted.
+type TagRelationshipScore struct {
+	Scorer        string         `json:"scorer"`
+	Score         float64        `json:"score"`
+	TotalArticles int64          `json:"total_articles"`
+	Tags          []string       `json:"tags"`
+	Pairs         []TagPairScore `json:"pairs"`
+}
+
+// TagPairScore adalah rincian skor satu pasangan tag. Counted false berarti
+// pasangan tidak bisa dinilai oleh scorer (salah satu tag belum pernah dipakai,
+// atau untuk varian PMI pasangannya belum pernah muncul bersama) dan tidak ikut
+// dirata-rata.
+type TagPairScore struct {
+	TagA          string  `json:"tag_a"`
+	TagB          string  `json:"tag_b"`
+	FrequencyA    int     `json:"frequency_a"`
+	FrequencyB    int     `json:"frequency_b"`
+	CoOccurrences int     `json:"co_occurrences"`
+	Score         float64 `json:"score"`
+	Counted       bool    `json:"counted"`
+}
+
+// TagUsageRecompute adalah ringkasan satu kali hitung ulang usage_count dan trending_score
+type TagUsageRecompute struct {
+	Tags         int       `json:"tags"`
+	Updated      int       `json:"updated"`
+	RecomputedAt time.Time `json:"recomputed_at"`
+}
+
+const (
+	TrendingSortCount    = "count"
+	TrendingSortVelocity = "velocity"
+)
+
+// TrendingTag adalah jumlah publish yang memakai tag di sebuah window. Velocity
+// adalah selisih Count dengan window sebelumnya yang sama panjang; positif
+// berarti tag sedang naik.
+type TrendingTag struct {
+	TagID         uint   `json:"tag_id"`
+	Name          string `json:"name"`
+	Count         int    `json:"count"`
+	PreviousCount int    `json:"previous_count"`
+	Velocity      int    `json:"velocity"`
+}
@@ -0,0 +1,10 @@
+package models
+
+import "time"
+
+// TrashedArticle adalah artikel yang sudah di-soft delete beserta waktu
+// penghapusannya, untuk ditampilkan di trash admin.
+type TrashedArticle struct {
+	Article
+	DeletedAt time.Time `json:"deleted_at"`
+}
@@ -0,0 +1,60 @@
+package models
+
+type DiffOp string
+
+const (
+	DiffEqual  DiffOp = "equal"
+	DiffInsert DiffOp = "insert"
+	DiffDelete DiffOp = "delete"
+)
+
+type DiffSegment struct {
+	Op   DiffOp `json:"op"`
+	Text string `json:"text"`
+}
+
+type DiffLine struct {
+	Op        DiffOp        `json:"op"`
+	OldNumber int           `json:"old_number,omitempty"`
+	NewNumber int           `json:"new_number,omitempty"`
+	Text      string        `json:"text"`
+	Words     []DiffSegment `json:"words,omitempty"`
+}
+
+type DiffHunk struct {
+	OldStart int        `json:"old_start"`
+	OldLines int        `json:"old_lines"`
+	NewStart int        `json:"new_start"`
+	NewLines int        `json:"new_lines"`
+	Lines    []DiffLine `json:"lines"`
+}
+
+type VersionRef struct {
+	ID            uint   `json:"id"`
+	VersionNumber int    `json:"version_number"`
+	Title         string `json:"title"`
+}
+
+type TitleDiff struct {
+	Changed bool          `json:"changed"`
+	Old     string        `json:"old"`
+	New     string        `json:"new"`
+	Words   []DiffSegment `json:"words,omitempty"`
+}
+
+type TagDiff struct {
+	Added     []string `json:"added"`
+	Removed   []string `json:"removed"`
+	Unchanged []string `json:"unchanged"`
+}
+
+// VersionDiff describes the changes needed to go from version From to version To.
+type VersionDiff struct {
+	ArticleID uint       `json:"article_id"`
+	From      VersionRef `json:"from"`
+	To        VersionRef `json:"to"`
+	Title     TitleDiff  `json:"title"`
+	Tags      TagDiff    `json:"tags"`
+	Hunks     []DiffHunk `json:"hunks"`
+	Unified   string     `json:"unified"`
+}
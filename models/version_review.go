@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// VersionReview mencatat setiap perpindahan status versi dalam workflow review,
+// termasuk komentar editor saat meminta perubahan.
+type VersionReview struct {
+	ID               uint          `json:"id" gorm:"primarykey"`
+	ArticleVersionID uint          `json:"article_version_id" gorm:"not null;index"`
+	ReviewerID       uint          `json:"reviewer_id" gorm:"not null"`
+	Reviewer         *User         `json:"reviewer,omitempty" gorm:"foreignKey:ReviewerID"`
+	FromStatus       VersionStatus `json:"from_status"`
+	ToStatus         VersionStatus `json:"to_status"`
+	Comment          string        `json:"comment"`
+	CreatedAt        time.Time     `json:"created_at"`
+}
@@ -8,29 +8,46 @@ import (
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type ArticleRepository interface {
 	Create(article *models.Article) (*models.Article, error)
 	GetByID(id uint) (*models.Article, error)
+	GetBySlug(slug string) (*models.Article, error)
+	GetSlugHistory(slug string) (*models.ArticleSlug, error)
+	IsSlugTaken(slug string, articleID uint) (bool, error)
+	ChangeSlug(articleID uint, oldSlug, newSlug string) error
 	GetList(params models.ArticleListParams, isPublic bool) ([]models.Article, int64, error)
+	GetPublishedForSitemap() ([]models.SitemapArticle, error)
 	Update(article *models.Article) error
 	Delete(id uint) error
+	GetTrashed(page, limit int) ([]models.TrashedArticle, int64, error)
+	GetTrashedIDsBefore(cutoff time.Time) ([]uint, error)
+	Restore(id uint) error
+	Purge(id uint) error
 	CreateVersion(version *models.ArticleVersion) error
 	GetVersions(articleID uint) ([]models.ArticleVersion, error)
+	GetLatestVersionNumber(articleID uint) (int, error)
+	LockForUpdate(articleID uint) (*models.Article, error)
 	GetVersion(articleID, versionID uint) (*models.ArticleVersion, error)
 	UpdateVersion(id uint, updates map[string]interface{}) error
 	GetVersionByID(versionID uint) (*models.ArticleVersion, error)
 	CountTagPairs() (map[string]map[string]int, error)
-	CountArticlesByTag() (map[uint]int, error)
-	GetTagsForArticle(articleID int) ([]string, error)
+	GetTagUsage() (map[uint]TagUsage, error)
 	GetTotalArticleCount() (int64, error)
-	GetArticleCountWithTag(tagName string) (int, error)
-	GetArticleCountWithTags(tag1, tag2 string) (int, error)
 	ClearPublishedVersionID(articleID uint) error
 	UpdateFields(id uint, fields map[string]interface{}) error
+	CreateAudits(audits []models.ArticleAudit) error
+	GetAudits(articleID uint) ([]models.ArticleAudit, error)
 	GetTagFrequencies(tagNames []string) (map[string]int, error)
 	GetTagPairCoOccurrences(tagNames []string) (map[string]int, error)
+	GetCoOccurringTags(tagNames []string) (map[string]map[string]int, error)
+	GetTagStatFrequencies(tagNames []string) (map[string]int, error)
+	GetTagStatPairCoOccurrences(tagNames []string) (map[string]int, error)
+	UpdateTagStats(oldVersionID, newVersionID uint) error
+	RebuildTagStats() error
+	GetPublishedTagMatches(tagNames []string, excludeArticleID uint) ([]PublishedTagMatch, error)
 }
 
 type articleRepository struct {
@@ -57,6 +74,64 @@ func (r *articleRepository) GetByID(id uint) (*models.Article, error) {
 	return &article, err
 }
 
+func (r *articleRepository) GetBySlug(slug string) (*models.Article, error) {
+	var article models.Article
+	err := r.db.Preload("Author").
+		Preload("PublishedVersion.Tags").
+		Preload("LatestVersion.Tags").
+		Where("slug = ?", slug).
+		First(&article).Error
+	return &article, err
+}
+
+func (r *articleRepository) GetSlugHistory(slug string) (*models.ArticleSlug, error) {
+	var history models.ArticleSlug
+	err := r.db.Where("slug = ?", slug).First(&history).Error
+	return &history, err
+}
+
+// IsSlugTaken mengecek apakah slug sudah dipakai artikel lain, baik sebagai slug
+// aktif (termasuk artikel yang sudah dihapus) maupun di riwayat slug.
+func (r *articleRepository) IsSlugTaken(slug string, articleID uint) (bool, error) {
+	var count int64
+	if err := r.db.Unscoped().Model(&models.Article{}).
+		Where("slug = ? AND id <> ?", slug, articleID).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	if count > 0 {
+		return true, nil
+	}
+
+	if err := r.db.Model(&models.ArticleSlug{}).
+		Where("slug = ? AND article_id <> ?", slug, articleID).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// ChangeSlug mengganti slug artikel dan menyimpan slug lama ke riwayat.
+// Jika slug baru pernah dipakai artikel ini sebelumnya, entri riwayatnya dihapus.
+func (r *articleRepository) ChangeSlug(articleID uint, oldSlug, newSlug string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("article_id = ? AND slug = ?", articleID, newSlug).
+			Delete(&models.ArticleSlug{}).Error; err != nil {
+			return err
+		}
+
+		if oldSlug != "" {
+			if err := tx.Create(&models.ArticleSlug{ArticleID: articleID, Slug: oldSlug}).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Model(&models.Article{}).
+			Where("id = ?", articleID).
+			Update("slug", newSlug).Error
+	})
+}
+
 // GetList mengambil daftar artikel dengan filter dan pagination sesuai params.
 // Fungsi ini meng-handle dua mode utama:
 // 1. Public mode (isPublic == true):
@@ -70,9 +145,13 @@ func (r *articleRepository) GetByID(id uint) (*models.Article, error) {
 //    - Jika tidak ada status filter, join ke latest_version_id hanya jika perlu (misal sorting berdasarkan skor atau filter tag).
 //
 // Selain itu, fungsi ini juga menangani:
-// - Filter berdasarkan AuthorID dan TagID, dengan join ke tabel tag yang sesuai alias article_versions yang aktif (av_pub atau av_lat).
-// - Sorting berdasarkan field yang diminta, termasuk field khusus seperti article_tag_relationship_score.
-// - Pagination dengan limit dan offset.
+// - Filter berdasarkan AuthorID dan TagID (opsional beserta tag turunannya), dicocokkan ke tag versi yang aktif (av_pub atau av_lat).
+// - Full-text search (params.Query) pada kolom search_vector versi yang aktif, dengan ranking ts_rank
+//   dan snippet ts_headline. SortBy "relevance" mengurutkan berdasarkan ranking tersebut.
+// - Sorting berdasarkan field yang diminta, termasuk field khusus seperti article_tag_relationship_score
+//   dan published_at (diambil dari versi yang aktif).
+// - Pagination dengan limit dan offset, atau keyset (params.After) yang melanjutkan setelah artikel
+//   terakhir halaman sebelumnya. Pada mode keyset Count dilewati dan total bernilai 0.
 // - Debug print query SQL sebelum dijalankan untuk membantu proses debugging.
 func (r *articleRepository) GetList(params models.ArticleListParams, isPublic bool) ([]models.Article, int64, error) {
 	var articles []models.Article
@@ -85,7 +164,8 @@ func (r *articleRepository) GetList(params models.ArticleListParams, isPublic bo
 	if isPublic {
 		// Public mode: hanya tampilkan artikel yang sudah published (published_version_id)
 		query = query.Joins("JOIN article_versions av_pub ON articles.published_version_id = av_pub.id").
-			Where("av_pub.status = ?", models.StatusPublished)
+			Where("av_pub.status = ?", models.StatusPublished).
+			Preload("PublishedVersion.Tags")
 	} else {
 		if params.Status == string(models.StatusPublished) {
 			// Kalau status published, join ke published_version_id
@@ -96,8 +176,8 @@ func (r *articleRepository) GetList(params models.ArticleListParams, isPublic bo
 			query = query.Joins("JOIN article_versions av_lat ON articles.latest_version_id = av_lat.id").
 				Where("av_lat.status = ?", params.Status)
 		} else {
-			// Kalau tidak ada status filter, join latest_version_id jika perlu sorting atau filter tag
-			if params.SortBy == "article_tag_relationship_score" || params.TagID > 0 {
+			// Kalau tidak ada status filter, join latest_version_id jika perlu sorting, filter tag atau search
+			if params.SortBy == "article_tag_relationship_score" || params.TagID > 0 || params.Query != "" {
 				query = query.Joins("JOIN article_versions av_lat ON articles.latest_version_id = av_lat.id")
 			}
 		}
@@ -107,51 +187,127 @@ func (r *articleRepository) GetList(params models.ArticleListParams, isPublic bo
 		query = query.Where("author_id = ?", params.AuthorID)
 	}
 
+	// Alias versi yang aktif untuk filter tag, search dan sorting
+	versionAlias := "av_lat"
+	if params.Status == string(models.StatusPublished) || isPublic {
+		versionAlias = "av_pub"
+	}
+
 	if params.TagID > 0 {
-		// Pakai alias sesuai join yang aktif
-		if params.Status == string(models.StatusPublished) || isPublic {
-			query = query.Joins("JOIN article_version_tags avt ON av_pub.id = avt.article_version_id").
-				Where("avt.tag_id = ?", params.TagID)
-		} else {
-			query = query.Joins("JOIN article_version_tags avt ON av_lat.id = avt.article_version_id").
-				Where("avt.tag_id = ?", params.TagID)
+		tagIDs := params.TagIDs
+		if len(tagIDs) == 0 {
+			tagIDs = []uint{params.TagID}
 		}
+		// EXISTS agar versi dengan beberapa tag turunan tidak muncul ganda
+		query = query.Where(fmt.Sprintf(`EXISTS (
+			SELECT 1 FROM article_version_tags avt
+			WHERE avt.article_version_id = %s.id AND avt.tag_id IN ?)`, versionAlias), tagIDs)
 	}
 
-	query.Count(&total)
-
-	sortBy := params.SortBy
-	if sortBy == "" {
-		sortBy = "created_at"
+	if params.Query != "" {
+		query = query.Where(fmt.Sprintf("%s.search_vector @@ websearch_to_tsquery('simple', ?)", versionAlias), params.Query)
 	}
 
-	sortOrder := params.SortOrder
+	sortOrder := strings.ToLower(params.SortOrder)
 	if sortOrder == "" {
 		sortOrder = "desc"
 	}
+	if sortOrder != "asc" && sortOrder != "desc" {
+		return nil, 0, fmt.Errorf("invalid sort_order %q", params.SortOrder)
+	}
 
-	if sortBy == "article_tag_relationship_score" {
-		if params.Status == string(models.StatusPublished) || isPublic {
-			query = query.Order(fmt.Sprintf("av_pub.article_tag_relationship_score %s", sortOrder))
-		} else {
-			query = query.Order(fmt.Sprintf("av_lat.article_tag_relationship_score %s", sortOrder))
-		}
+	sortExpr, sortArgs, sortType, err := articleSortColumn(params, isPublic, versionAlias)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if params.After == nil {
+		query.Count(&total)
 	} else {
-		query = query.Order(fmt.Sprintf("articles.%s %s", sortBy, sortOrder))
+		// Keyset: lanjut setelah (nilai sort, id) artikel terakhir halaman sebelumnya
+		operator := "<"
+		if sortOrder == "asc" {
+			operator = ">"
+		}
+		keysetArgs := append(append([]interface{}{}, sortArgs...), params.After.Value, params.After.ID)
+		query = query.Where(fmt.Sprintf("(%s, articles.id) %s (CAST(? AS %s), ?)", sortExpr, operator, sortType), keysetArgs...)
 	}
 
-	offset := (params.Page - 1) * params.Limit
+	// sort_key disimpan sebagai teks agar bisa dimasukkan ke next_cursor lalu di-cast balik
+	selects := []string{"articles.*", fmt.Sprintf("(%s)::text AS sort_key", sortExpr)}
+	selectArgs := append([]interface{}{}, sortArgs...)
+	if params.Query != "" {
+		// Tag HTML dibuang dulu supaya snippet hanya berisi teks dan penanda <mark>
+		selects = append(selects, fmt.Sprintf(`ts_rank(%[1]s.search_vector, websearch_to_tsquery('simple', ?)) AS search_rank,
+			ts_headline('simple', regexp_replace(coalesce(%[1]s.content, ''), '<[^>]*>', ' ', 'g'),
+				websearch_to_tsquery('simple', ?),
+				'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=30, MinWords=10') AS snippet`, versionAlias))
+		selectArgs = append(selectArgs, params.Query, params.Query)
+	}
+	query = query.Select(strings.Join(selects, ", "), selectArgs...)
+
+	// articles.id sebagai tie-breaker supaya urutan stabil dan cursor tidak melewatkan artikel
+	query = query.Order(clause.OrderBy{Expression: clause.Expr{
+		SQL:                fmt.Sprintf("%s %s, articles.id %s", sortExpr, sortOrder, sortOrder),
+		Vars:               sortArgs,
+		WithoutParentheses: true,
+	}})
+
+	offset := 0
+	if params.After == nil && params.Page > 1 {
+		offset = (params.Page - 1) * params.Limit
+	}
 
 	// Debug SQL
 	stmt := query.Session(&gorm.Session{DryRun: true}).Offset(offset).Limit(params.Limit).Find(&articles).Statement
 	fmt.Println("SQL:", stmt.SQL.String())
 	fmt.Println("Vars:", stmt.Vars)
 
-	err := query.Debug().Offset(offset).Limit(params.Limit).Find(&articles).Error
+	err = query.Debug().Offset(offset).Limit(params.Limit).Find(&articles).Error
 
 	return articles, total, err
 }
 
+// articleSortColumn memetakan sort_by ke ekspresi SQL (beserta argumennya) dan
+// tipe kolomnya. Ekspresi yang sama dipakai untuk ORDER BY, sort_key dan
+// kondisi keyset, jadi nilainya tidak boleh NULL.
+func articleSortColumn(params models.ArticleListParams, isPublic bool, versionAlias string) (string, []interface{}, string, error) {
+	switch params.SortBy {
+	case "", "created_at":
+		return "articles.created_at", nil, "timestamp", nil
+	case "updated_at":
+		return "articles.updated_at", nil, "timestamp", nil
+	case "title":
+		return "articles.title", nil, "text", nil
+	case "published_at":
+		// published_at ada di tabel versi, bukan di articles
+		if isPublic || params.Status != "" {
+			return fmt.Sprintf("COALESCE(%s.published_at, articles.created_at)", versionAlias), nil, "timestamp", nil
+		}
+		return "articles.created_at", nil, "timestamp", nil
+	case "article_tag_relationship_score":
+		return fmt.Sprintf("COALESCE(%s.article_tag_relationship_score, 0)", versionAlias), nil, "numeric", nil
+	case "relevance":
+		if params.Query != "" {
+			return fmt.Sprintf("ts_rank(%s.search_vector, websearch_to_tsquery('simple', ?))", versionAlias), []interface{}{params.Query}, "real", nil
+		}
+	}
+	return "", nil, "", fmt.Errorf("unsupported sort_by %q", params.SortBy)
+}
+
+// GetPublishedForSitemap mengambil semua artikel published tanpa preload,
+// lastmod diambil dari versi yang sedang dipublikasikan.
+func (r *articleRepository) GetPublishedForSitemap() ([]models.SitemapArticle, error) {
+	var results []models.SitemapArticle
+	err := r.db.Model(&models.Article{}).
+		Select("articles.id, articles.slug, COALESCE(av_pub.published_at, av_pub.updated_at) AS last_mod").
+		Joins("JOIN article_versions av_pub ON articles.published_version_id = av_pub.id").
+		Where("av_pub.status = ?", models.StatusPublished).
+		Order("articles.id asc").
+		Scan(&results).Error
+	return results, err
+}
+
 func (r *articleRepository) Update(article *models.Article) error {
 	return r.db.Save(article).Error
 }
@@ -163,10 +319,107 @@ func (r *articleRepository) UpdateFields(id uint, fields map[string]interface{})
 		Error
 }
 
+func (r *articleRepository) CreateAudits(audits []models.ArticleAudit) error {
+	if len(audits) == 0 {
+		return nil
+	}
+	return r.db.Create(&audits).Error
+}
+
+func (r *articleRepository) GetAudits(articleID uint) ([]models.ArticleAudit, error) {
+	var audits []models.ArticleAudit
+	err := r.db.Where("article_id = ?", articleID).
+		Preload("Actor").
+		Order("created_at DESC, id DESC").
+		Find(&audits).Error
+	return audits, err
+}
+
 func (r *articleRepository) Delete(id uint) error {
 	return r.db.Delete(&models.Article{}, id).Error
 }
 
+// GetTrashed mengambil artikel yang sudah di-soft delete, terbaru lebih dulu.
+func (r *articleRepository) GetTrashed(page, limit int) ([]models.TrashedArticle, int64, error) {
+	query := r.db.Unscoped().Model(&models.Article{}).Where("deleted_at IS NOT NULL")
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var articles []models.Article
+	err := query.Preload("Author").
+		Order("deleted_at DESC, id DESC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&articles).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	trashed := make([]models.TrashedArticle, len(articles))
+	for i, article := range articles {
+		trashed[i] = models.TrashedArticle{Article: article, DeletedAt: article.DeletedAt.Time}
+	}
+	return trashed, total, nil
+}
+
+func (r *articleRepository) GetTrashedIDsBefore(cutoff time.Time) ([]uint, error) {
+	var ids []uint
+	err := r.db.Unscoped().Model(&models.Article{}).
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", cutoff).
+		Order("deleted_at ASC").
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+// Restore mengembalikan artikel dari trash beserta semua versinya.
+func (r *articleRepository) Restore(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Unscoped().Model(&models.Article{}).
+			Where("id = ? AND deleted_at IS NOT NULL", id).
+			Update("deleted_at", nil)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		return tx.Unscoped().Model(&models.ArticleVersion{}).
+			Where("article_id = ? AND deleted_at IS NOT NULL", id).
+			Update("deleted_at", nil).Error
+	})
+}
+
+// Purge menghapus permanen artikel yang ada di trash. Tag versi dihapus
+// eksplisit, riwayat slug, audit dan review ikut terhapus lewat ON DELETE CASCADE.
+func (r *articleRepository) Purge(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var article models.Article
+		err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND deleted_at IS NOT NULL", id).
+			First(&article).Error
+		if err != nil {
+			return err
+		}
+
+		versionIDs := tx.Unscoped().Model(&models.ArticleVersion{}).Select("id").Where("article_id = ?", id)
+		if err := tx.Where("article_version_id IN (?)", versionIDs).
+			Delete(&models.ArticleVersionTag{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Unscoped().Where("article_id = ?", id).
+			Delete(&models.ArticleVersion{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Unscoped().Delete(&models.Article{}, id).Error
+	})
+}
+
 func (r *articleRepository) CreateVersion(version *models.ArticleVersion) error {
 	return r.db.Create(version).Error
 }
@@ -180,6 +433,28 @@ func (r *articleRepository) GetVersions(articleID uint) ([]models.ArticleVersion
 	return versions, err
 }
 
+// LockForUpdate mengunci baris artikel (SELECT ... FOR UPDATE) sampai transaksi
+// selesai, sehingga alokasi nomor versi untuk artikel yang sama berjalan berurutan.
+// Artikel dikembalikan tanpa relasi, cukup untuk cek latest version dan ETag.
+func (r *articleRepository) LockForUpdate(articleID uint) (*models.Article, error) {
+	var article models.Article
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&article, articleID).Error
+	return &article, err
+}
+
+// GetLatestVersionNumber mengembalikan nomor versi tertinggi sebuah artikel
+// (0 jika belum ada), termasuk versi yang sudah di-soft delete karena tetap
+// terkena unique constraint (article_id, version_number).
+func (r *articleRepository) GetLatestVersionNumber(articleID uint) (int, error) {
+	var latest int
+	err := r.db.Unscoped().Model(&models.ArticleVersion{}).
+		Select("COALESCE(MAX(version_number), 0)").
+		Where("article_id = ?", articleID).
+		Scan(&latest).Error
+	return latest, err
+}
+
 func (r *articleRepository) GetVersion(articleID, versionID uint) (*models.ArticleVersion, error) {
 	var version models.ArticleVersion
 	err := r.db.Where("article_id = ? AND id = ?", articleID, versionID).
@@ -242,19 +517,29 @@ func (r *articleRepository) CountTagPairs() (map[string]map[string]int, error) {
 	return tagPairs, nil
 }
 
-func (r *articleRepository) CountArticlesByTag() (map[uint]int, error) {
-	var results []struct {
-		TagID uint
-		Count int
-	}
+// TagUsage adalah jumlah versi published yang memakai sebuah tag dan waktu
+// publish terbarunya
+type TagUsage struct {
+	TagID      uint
+	Count      int
+	LastUsedAt *time.Time
+}
+
+// GetTagUsage menghitung pemakaian tag di versi published artikel yang belum dihapus
+func (r *articleRepository) GetTagUsage() (map[uint]TagUsage, error) {
+	var results []TagUsage
 
 	query := `
 		SELECT 
 			avt.tag_id,
-			COUNT(*) as count
+			COUNT(*) as count,
+			MAX(av.published_at) as last_used_at
 		FROM article_version_tags avt
 		JOIN article_versions av ON avt.article_version_id = av.id
+		JOIN articles a ON a.id = av.article_id
 		WHERE av.status = 'published'
+			AND av.deleted_at IS NULL
+			AND a.deleted_at IS NULL
 		GROUP BY avt.tag_id
 	`
 
@@ -263,37 +548,12 @@ func (r *articleRepository) CountArticlesByTag() (map[uint]int, error) {
 		return nil, err
 	}
 
-	counts := make(map[uint]int)
+	usage := make(map[uint]TagUsage, len(results))
 	for _, result := range results {
-		counts[result.TagID] = result.Count
-	}
-
-	return counts, nil
-}
-
-func (r *articleRepository) GetTagsForArticle(articleID int) ([]string, error) {
-	var tags []string
-
-	const query = `
-		SELECT t.name
-		FROM articles a
-		JOIN article_versions av ON av.id = a.latest_version_id
-		JOIN article_version_tags avt ON avt.article_version_id = av.id
-		JOIN tags t ON t.id = avt.tag_id
-		WHERE a.id = $1
-		  AND a.deleted_at IS NULL
-		  AND av.deleted_at IS NULL
-		  AND t.deleted_at IS NULL
-		ORDER BY t.name;
-	`
-
-	err := r.db.Raw(query, articleID).Scan(&tags).Error
-	if err != nil {
-		log.Printf("error fetching tags for article %d: %v", articleID, err)
-		return nil, err
+		usage[result.TagID] = result
 	}
 
-	return tags, nil
+	return usage, nil
 }
 
 func (r *articleRepository) GetTotalArticleCount() (int64, error) {
@@ -306,56 +566,6 @@ func (r *articleRepository) GetTotalArticleCount() (int64, error) {
 	return count, nil
 }
 
-func (r *articleRepository) GetArticleCountWithTag(tagName string) (int, error) {
-	var count int
-
-	const query = `
-		SELECT COUNT(DISTINCT a.id)
-		FROM articles a
-		JOIN article_versions av ON av.id = a.latest_version_id
-		JOIN article_version_tags avt ON avt.article_version_id = av.id
-		JOIN tags t ON t.id = avt.tag_id
-		WHERE t.name = $1
-		  AND a.deleted_at IS NULL
-		  AND av.deleted_at IS NULL
-		  AND t.deleted_at IS NULL;
-	`
-
-	err := r.db.Raw(query, tagName).Scan(&count).Error
-	if err != nil {
-		return 0, err
-	}
-
-	return count, nil
-}
-
-func (r *articleRepository) GetArticleCountWithTags(tag1, tag2 string) (int, error) {
-	var count int
-
-	const query = `
-		SELECT COUNT(DISTINCT a.id)
-		FROM articles a
-		JOIN article_versions av ON av.id = a.latest_version_id
-		JOIN article_version_tags avt1 ON avt1.article_version_id = av.id
-		JOIN tags t1 ON t1.id = avt1.tag_id
-		JOIN article_version_tags avt2 ON avt2.article_version_id = av.id
-		JOIN tags t2 ON t2.id = avt2.tag_id
-		WHERE a.deleted_at IS NULL
-		  AND av.deleted_at IS NULL
-		  AND t1.deleted_at IS NULL
-		  AND t2.deleted_at IS NULL
-		  AND t1.name = $1
-		  AND t2.name = $2;
-	`
-
-	err := r.db.Raw(query, tag1, tag2).Scan(&count).Error
-	if err != nil {
-		return 0, err
-	}
-
-	return count, nil
-}
-
 func (r *articleRepository) ClearPublishedVersionID(articleID uint) error {
 	return r.db.Model(&models.Article{}).Where("id = ?", articleID).Update("published_version_id", nil).Error
 }
@@ -471,3 +681,35 @@ func (r *articleRepository) GetTagPairCoOccurrences(tagNames []string) (map[stri
 
 	return result, nil
 }
+
+// PublishedTagMatch adalah satu tag (dari tagNames) pada versi published sebuah artikel
+type PublishedTagMatch struct {
+	ArticleID   uint
+	PublishedAt *time.Time
+	TagName     string
+}
+
+// GetPublishedTagMatches - ambil artikel published (selain excludeArticleID)
+// yang versi published-nya memiliki salah satu tagNames
+func (r *articleRepository) GetPublishedTagMatches(tagNames []string, excludeArticleID uint) ([]PublishedTagMatch, error) {
+	var matches []PublishedTagMatch
+	if len(tagNames) == 0 {
+		return matches, nil
+	}
+
+	query := `
+		SELECT a.id AS article_id, av.published_at, t.name AS tag_name
+		FROM articles a
+		JOIN article_versions av ON av.id = a.published_version_id
+		JOIN article_version_tags avt ON avt.article_version_id = av.id
+		JOIN tags t ON t.id = avt.tag_id
+		WHERE t.name IN (?)
+		  AND a.id <> ?
+		  AND av.status = ?
+		  AND a.deleted_at IS NULL
+		  AND av.deleted_at IS NULL
+		  AND t.deleted_at IS NULL
+	`
+	err := r.db.Raw(query, tagNames, excludeArticleID, models.StatusPublished).Scan(&matches).Error
+	return matches, err
+}
@@ -2,12 +2,26 @@ package repositories
 
 import (
 	"cisdi-test-cms/models"
+	"time"
 
 	"gorm.io/gorm"
 )
 
 type ArticleVersionRepository interface {
 	DeleteVersionsByArticleID(articleID uint) error
+	GetDueScheduledPublishes(now time.Time) ([]models.ArticleVersion, error)
+	GetDueScheduledUnpublishes(now time.Time) ([]models.ArticleVersion, error)
+	GetPendingSchedules(authorID uint) ([]models.ArticleVersion, error)
+	CreateReview(review *models.VersionReview) error
+	GetReviews(versionID uint) ([]models.VersionReview, error)
+	GetVersionTagNames(afterID uint, limit int) ([]VersionTagNames, error)
+	UpdateRelationshipScore(versionID uint, score float64) error
+}
+
+// VersionTagNames - nama tag (yang belum dihapus) milik satu versi artikel
+type VersionTagNames struct {
+	VersionID uint
+	Tags      []string
 }
 
 type articleVersionRepository struct {
@@ -21,3 +35,96 @@ func NewArticleVersionRepository(db *gorm.DB) ArticleVersionRepository {
 func (r *articleVersionRepository) DeleteVersionsByArticleID(articleID uint) error {
 	return r.db.Where("article_id = ?", articleID).Delete(&models.ArticleVersion{}).Error
 }
+
+func (r *articleVersionRepository) GetDueScheduledPublishes(now time.Time) ([]models.ArticleVersion, error) {
+	var versions []models.ArticleVersion
+	err := r.db.Where("publish_at IS NOT NULL AND publish_at <= ?", now).
+		Order("publish_at asc").
+		Find(&versions).Error
+	return versions, err
+}
+
+func (r *articleVersionRepository) GetDueScheduledUnpublishes(now time.Time) ([]models.ArticleVersion, error) {
+	var versions []models.ArticleVersion
+	err := r.db.Where("unpublish_at IS NOT NULL AND unpublish_at <= ?", now).
+		Where("publish_at IS NULL").
+		Order("unpublish_at asc").
+		Find(&versions).Error
+	return versions, err
+}
+
+// GetPendingSchedules mengambil versi yang masih punya jadwal publish/unpublish.
+// authorID = 0 berarti semua penulis.
+func (r *articleVersionRepository) GetPendingSchedules(authorID uint) ([]models.ArticleVersion, error) {
+	var versions []models.ArticleVersion
+	query := r.db.Model(&models.ArticleVersion{}).
+		Joins("JOIN articles ON articles.id = article_versions.article_id AND articles.deleted_at IS NULL").
+		Where("(article_versions.publish_at IS NOT NULL OR article_versions.unpublish_at IS NOT NULL)")
+
+	if authorID > 0 {
+		query = query.Where("articles.author_id = ?", authorID)
+	}
+
+	err := query.Preload("Tags").
+		Order("COALESCE(article_versions.publish_at, article_versions.unpublish_at) asc").
+		Find(&versions).Error
+	return versions, err
+}
+
+func (r *articleVersionRepository) CreateReview(review *models.VersionReview) error {
+	return r.db.Create(review).Error
+}
+
+func (r *articleVersionRepository) GetReviews(versionID uint) ([]models.VersionReview, error) {
+	var reviews []models.VersionReview
+	err := r.db.Where("article_version_id = ?", versionID).
+		Preload("Reviewer").
+		Order("created_at asc, id asc").
+		Find(&reviews).Error
+	return reviews, err
+}
+
+// GetVersionTagNames mengambil paling banyak limit versi dengan id > afterID
+// (urut id) beserta nama tag-nya, untuk diproses bertahap
+func (r *articleVersionRepository) GetVersionTagNames(afterID uint, limit int) ([]VersionTagNames, error) {
+	var ids []uint
+	err := r.db.Model(&models.ArticleVersion{}).
+		Where("id > ?", afterID).
+		Order("id asc").
+		Limit(limit).
+		Pluck("id", &ids).Error
+	if err != nil || len(ids) == 0 {
+		return nil, err
+	}
+
+	var rows []struct {
+		ArticleVersionID uint
+		Name             string
+	}
+	err = r.db.Table("article_version_tags avt").
+		Select("avt.article_version_id, t.name").
+		Joins("JOIN tags t ON t.id = avt.tag_id").
+		Where("avt.article_version_id IN ? AND t.deleted_at IS NULL", ids).
+		Order("t.name").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	tagsByVersion := make(map[uint][]string, len(ids))
+	for _, row := range rows {
+		tagsByVersion[row.ArticleVersionID] = append(tagsByVersion[row.ArticleVersionID], row.Name)
+	}
+	versions := make([]VersionTagNames, 0, len(ids))
+	for _, id := range ids {
+		versions = append(versions, VersionTagNames{VersionID: id, Tags: tagsByVersion[id]})
+	}
+	return versions, nil
+}
+
+// UpdateRelationshipScore menyimpan skor baru tanpa mengubah updated_at versi
+func (r *articleVersionRepository) UpdateRelationshipScore(versionID uint, score float64) error {
+	return r.db.Model(&models.ArticleVersion{}).
+		Where("id = ?", versionID).
+		UpdateColumn("article_tag_relationship_score", score).Error
+}
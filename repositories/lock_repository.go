@@ -0,0 +1,35 @@
+package repositories
+
+import (
+	"gorm.io/gorm"
+)
+
+type LockRepository interface {
+	WithAdvisoryLock(key int64, fn func() error) (bool, error)
+}
+
+type lockRepository struct {
+	db *gorm.DB
+}
+
+func NewLockRepository(db *gorm.DB) LockRepository {
+	return &lockRepository{db: db}
+}
+
+// WithAdvisoryLock menjalankan fn hanya jika advisory lock Postgres untuk key
+// berhasil didapat. Lock dipegang selama transaksi berjalan sehingga otomatis
+// dilepas ketika fn selesai, termasuk saat koneksi/replica mati di tengah jalan.
+// Nilai bool menandakan apakah lock didapat (dan fn dijalankan).
+func (r *lockRepository) WithAdvisoryLock(key int64, fn func() error) (bool, error) {
+	acquired := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Raw("SELECT pg_try_advisory_xact_lock(?)", key).Scan(&acquired).Error; err != nil {
+			return err
+		}
+		if !acquired {
+			return nil
+		}
+		return fn()
+	})
+	return acquired, err
+}
@@ -2,18 +2,41 @@ package repositories
 
 import (
 	"cisdi-test-cms/models"
+	"errors"
+	"time"
 
 	"gorm.io/gorm"
 )
 
+// ErrTagCycle dikembalikan jika parent baru adalah tag itu sendiri atau turunannya
+var ErrTagCycle = errors.New("tag hierarchy cannot contain a cycle")
+
+// tagHierarchyLockKey men-serialisasi perubahan parent, agar dua update yang
+// berjalan bersamaan (A -> B dan B -> A) tidak lolos cek cycle sekaligus
+const tagHierarchyLockKey = 7_318_004_101
+
 type TagRepository interface {
 	Create(tag *models.Tag) error
 	GetByName(name string) (*models.Tag, error)
+	GetByNameUnscoped(name string) (*models.Tag, error)
 	GetByNames(names []string) ([]models.Tag, error)
+	GetByAlias(alias string) (*models.Tag, error)
 	GetByID(id uint) (*models.Tag, error)
 	GetAll() ([]models.Tag, error)
 	Update(tag *models.Tag) error
-	BulkUpdate(tags []models.Tag) error
+	UpdateUsage(tags []models.Tag) error
+	Rename(id uint, oldName, newName string) error
+	Merge(sourceID, targetID uint) error
+	Delete(id uint) error
+	Restore(id uint) error
+	GetAlias(alias string) (*models.TagAlias, error)
+	CreateAlias(alias *models.TagAlias) error
+	DeleteAlias(tagID, aliasID uint) error
+	SetParent(id uint, parentID *uint) error
+	GetDescendantIDs(id uint) ([]uint, error)
+	RecordDailyUsage(versionID uint, day time.Time) error
+	GetTrending(params models.TrendingTagParams, currentFrom, previousFrom time.Time) ([]models.TrendingTag, error)
+	DeleteDailyUsageBefore(day time.Time) error
 }
 
 type tagRepository struct {
@@ -34,15 +57,34 @@ func (r *tagRepository) GetByName(name string) (*models.Tag, error) {
 	return &tag, err
 }
 
+// GetByNameUnscoped juga mengembalikan tag yang sudah di-soft delete, karena
+// namanya tetap terkena unique index.
+func (r *tagRepository) GetByNameUnscoped(name string) (*models.Tag, error) {
+	var tag models.Tag
+	err := r.db.Unscoped().Where("name = ?", name).First(&tag).Error
+	return &tag, err
+}
+
 func (r *tagRepository) GetByNames(names []string) ([]models.Tag, error) {
 	var tags []models.Tag
 	err := r.db.Where("name IN ?", names).Find(&tags).Error
 	return tags, err
 }
 
+// GetByAlias mengembalikan tag kanonik dari sebuah alias.
+func (r *tagRepository) GetByAlias(alias string) (*models.Tag, error) {
+	var tag models.Tag
+	err := r.db.Joins("JOIN tag_aliases ta ON ta.tag_id = tags.id").
+		Where("ta.alias = ?", alias).
+		First(&tag).Error
+	return &tag, err
+}
+
 func (r *tagRepository) GetByID(id uint) (*models.Tag, error) {
 	var tag models.Tag
-	err := r.db.First(&tag, id).Error
+	err := r.db.Preload("Aliases", func(db *gorm.DB) *gorm.DB {
+		return db.Order("alias asc")
+	}).First(&tag, id).Error
 	return &tag, err
 }
 
@@ -56,6 +98,256 @@ func (r *tagRepository) Update(tag *models.Tag) error {
 	return r.db.Save(tag).Error
 }
 
-func (r *tagRepository) BulkUpdate(tags []models.Tag) error {
-	return r.db.Save(&tags).Error
+// UpdateUsage menyimpan usage_count, trending_score dan last_used_at. Memakai
+// UpdateColumns agar updated_at tidak ikut berubah.
+func (r *tagRepository) UpdateUsage(tags []models.Tag) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for _, tag := range tags {
+			err := tx.Model(&models.Tag{}).Where("id = ?", tag.ID).UpdateColumns(map[string]interface{}{
+				"usage_count":    tag.UsageCount,
+				"trending_score": tag.TrendingScore,
+				"last_used_at":   tag.LastUsedAt,
+			}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Rename mengganti nama tag dan menyimpan nama lama sebagai alias, sehingga
+// input dengan nama lama tetap masuk ke tag ini.
+func (r *tagRepository) Rename(id uint, oldName, newName string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// Nama baru mungkin sebelumnya alias tag ini sendiri
+		if err := tx.Where("tag_id = ? AND alias = ?", id, newName).
+			Delete(&models.TagAlias{}).Error; err != nil {
+			return err
+		}
+
+		// UpdateColumn agar updated_at tidak ikut berubah; rename hanya mengganti nama,
+		// metadata tag lainnya dipertahankan apa adanya
+		if err := tx.Model(&models.Tag{}).
+			Where("id = ?", id).
+			UpdateColumn("name", newName).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(&models.TagAlias{TagID: id, Alias: oldName}).Error
+	})
+}
+
+// Merge memindahkan semua pemakaian dan alias tag sumber ke tag tujuan, lalu
+// menghapus tag sumber dan menjadikan namanya alias tag tujuan.
+func (r *tagRepository) Merge(sourceID, targetID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var source models.Tag
+		if err := tx.First(&source, sourceID).Error; err != nil {
+			return err
+		}
+
+		// Versi yang sudah memiliki kedua tag cukup mempertahankan tag tujuan,
+		// agar update di bawah tidak melanggar unique_article_version_tag
+		if err := tx.Exec(`
+			DELETE FROM article_version_tags
+			WHERE tag_id = ?
+				AND article_version_id IN (
+					SELECT article_version_id FROM article_version_tags WHERE tag_id = ?
+				)`, sourceID, targetID).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&models.ArticleVersionTag{}).
+			Where("tag_id = ?", sourceID).
+			Update("tag_id", targetID).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&models.TagAlias{}).
+			Where("tag_id = ?", sourceID).
+			Update("tag_id", targetID).Error; err != nil {
+			return err
+		}
+
+		// Jika tujuan adalah anak sumber, tujuan naik ke posisi sumber;
+		// anak-anak sumber lainnya pindah ke tujuan
+		if err := tx.Model(&models.Tag{}).
+			Where("id = ? AND parent_id = ?", targetID, sourceID).
+			UpdateColumn("parent_id", source.ParentID).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Tag{}).
+			Where("parent_id = ?", sourceID).
+			UpdateColumn("parent_id", targetID).Error; err != nil {
+			return err
+		}
+
+		// Riwayat trending harian sumber digabung ke tujuan
+		if err := tx.Exec(`
+			INSERT INTO tag_daily_usage (tag_id, day, publish_count)
+			SELECT ?, day, publish_count FROM tag_daily_usage WHERE tag_id = ?
+			ON CONFLICT (tag_id, day) DO UPDATE
+			SET publish_count = tag_daily_usage.publish_count + EXCLUDED.publish_count`, targetID, sourceID).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Unscoped().Delete(&models.Tag{}, sourceID).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(&models.TagAlias{TagID: targetID, Alias: source.Name}).Error
+	})
+}
+
+// Delete melakukan soft delete tag beserta aliasnya. Relasi ke versi tetap
+// disimpan sehingga tag kembali lengkap jika namanya dipakai lagi. Anak tag
+// dipindah ke parent tag yang dihapus.
+func (r *tagRepository) Delete(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var tag models.Tag
+		if err := tx.First(&tag, id).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&models.Tag{}).
+			Where("parent_id = ?", id).
+			UpdateColumn("parent_id", tag.ParentID).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("tag_id = ?", id).Delete(&models.TagAlias{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Tag{}, id).Error
+	})
+}
+
+func (r *tagRepository) Restore(id uint) error {
+	return r.db.Unscoped().Model(&models.Tag{}).
+		Where("id = ?", id).
+		Update("deleted_at", nil).Error
+}
+
+func (r *tagRepository) GetAlias(alias string) (*models.TagAlias, error) {
+	var tagAlias models.TagAlias
+	err := r.db.Where("alias = ?", alias).First(&tagAlias).Error
+	return &tagAlias, err
+}
+
+func (r *tagRepository) CreateAlias(alias *models.TagAlias) error {
+	return r.db.Create(alias).Error
+}
+
+func (r *tagRepository) DeleteAlias(tagID, aliasID uint) error {
+	result := r.db.Where("id = ? AND tag_id = ?", aliasID, tagID).Delete(&models.TagAlias{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// SetParent memindahkan tag ke bawah parentID (nil = root) dan menolak
+// perubahan yang membentuk cycle.
+func (r *tagRepository) SetParent(id uint, parentID *uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", tagHierarchyLockKey).Error; err != nil {
+			return err
+		}
+
+		if parentID != nil {
+			var cycle bool
+			err := tx.Raw(`
+				WITH RECURSIVE ancestors AS (
+					SELECT id, parent_id FROM tags WHERE id = ?
+					UNION
+					SELECT t.id, t.parent_id FROM tags t JOIN ancestors a ON t.id = a.parent_id
+				)
+				SELECT EXISTS (SELECT 1 FROM ancestors WHERE id = ?)`, *parentID, id).
+				Scan(&cycle).Error
+			if err != nil {
+				return err
+			}
+			if cycle {
+				return ErrTagCycle
+			}
+		}
+
+		return tx.Model(&models.Tag{}).
+			Where("id = ?", id).
+			UpdateColumn("parent_id", parentID).Error
+	})
+}
+
+// GetDescendantIDs mengembalikan id tag beserta semua turunannya yang masih aktif.
+func (r *tagRepository) GetDescendantIDs(id uint) ([]uint, error) {
+	var ids []uint
+	err := r.db.Raw(`
+		WITH RECURSIVE descendants AS (
+			SELECT id FROM tags WHERE id = ? AND deleted_at IS NULL
+			UNION
+			SELECT t.id FROM tags t JOIN descendants d ON t.parent_id = d.id
+			WHERE t.deleted_at IS NULL
+		)
+		SELECT id FROM descendants`, id).
+		Scan(&ids).Error
+	return ids, err
+}
+
+// trendingOrders memetakan parameter sort ke ORDER BY GetTrending
+var trendingOrders = map[string]string{
+	models.TrendingSortCount:    "count DESC, velocity DESC, t.name ASC",
+	models.TrendingSortVelocity: "velocity DESC, count DESC, t.name ASC",
+}
+
+// RecordDailyUsage menambah bucket harian (tanggal UTC) setiap tag di versi
+// yang baru dipublikasikan
+func (r *tagRepository) RecordDailyUsage(versionID uint, day time.Time) error {
+	return r.db.Exec(`
+		INSERT INTO tag_daily_usage (tag_id, day, publish_count)
+		SELECT tag_id, ?, 1 FROM article_version_tags
+		WHERE article_version_id = ?
+		ORDER BY tag_id
+		ON CONFLICT (tag_id, day) DO UPDATE
+		SET publish_count = tag_daily_usage.publish_count + 1`, day.UTC().Format("2006-01-02"), versionID).Error
+}
+
+// GetTrending menjumlahkan publish per tag sejak currentFrom (window sekarang)
+// dan antara previousFrom dan currentFrom (window sebelumnya). Hanya tag yang
+// dipakai di window sekarang yang dikembalikan.
+func (r *tagRepository) GetTrending(params models.TrendingTagParams, currentFrom, previousFrom time.Time) ([]models.TrendingTag, error) {
+	order, ok := trendingOrders[params.Sort]
+	if !ok {
+		order = trendingOrders[models.TrendingSortCount]
+	}
+
+	var trending []models.TrendingTag
+	err := r.db.Raw(`
+		SELECT t.id AS tag_id, t.name, u.count, u.previous_count, u.count - u.previous_count AS velocity
+		FROM (
+			SELECT tag_id,
+			       SUM(CASE WHEN day >= @current THEN publish_count ELSE 0 END) AS count,
+			       SUM(CASE WHEN day < @current THEN publish_count ELSE 0 END) AS previous_count
+			FROM tag_daily_usage
+			WHERE day >= @previous
+			GROUP BY tag_id
+		) u
+		JOIN tags t ON t.id = u.tag_id
+		WHERE t.deleted_at IS NULL
+		  AND u.count > 0
+		ORDER BY `+order+`
+		LIMIT @limit`, map[string]interface{}{
+		"current":  currentFrom.UTC().Format("2006-01-02"),
+		"previous": previousFrom.UTC().Format("2006-01-02"),
+		"limit":    params.Limit,
+	}).Scan(&trending).Error
+	return trending, err
+}
+
+// DeleteDailyUsageBefore menghapus bucket yang sudah di luar window terpanjang
+func (r *tagRepository) DeleteDailyUsageBefore(day time.Time) error {
+	return r.db.Exec("DELETE FROM tag_daily_usage WHERE day < ?", day.UTC().Format("2006-01-02")).Error
 }
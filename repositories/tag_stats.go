@@ -0,0 +1,217 @@
+package repositories
+
+import "gorm.io/gorm"
+
+// Statistik tag (tag_stats dan tag_pair_stats) menyimpan jumlah artikel per tag
+// dan per pasangan tag, dihitung dari latest version artikel yang belum dihapus,
+// sama seperti GetTagFrequencies dan GetTagPairCoOccurrences. Statistik
+// di-update bertahap setiap latest version berubah atau artikel dihapus/di-restore,
+// sehingga scorer tidak perlu agregasi ke seluruh artikel. Tag yang di-soft
+// delete disaring saat dibaca.
+
+// UpdateTagStats memindahkan hitungan satu artikel dari tag oldVersionID ke tag
+// newVersionID (0 berarti tidak ada). Harus dipanggil di transaksi yang sama
+// dengan perubahan latest_version_id. Baris di-upsert urut tag_id agar dua
+// transaksi yang bersamaan tidak saling deadlock.
+func (r *articleRepository) UpdateTagStats(oldVersionID, newVersionID uint) error {
+	if oldVersionID == newVersionID {
+		return nil
+	}
+
+	err := r.db.Exec(`
+		INSERT INTO tag_stats (tag_id, article_count)
+		SELECT tag_id, SUM(delta)
+		FROM (
+			SELECT tag_id, -1 AS delta FROM article_version_tags WHERE article_version_id = @old
+			UNION ALL
+			SELECT tag_id, 1 AS delta FROM article_version_tags WHERE article_version_id = @new
+		) d
+		GROUP BY tag_id
+		HAVING SUM(delta) <> 0
+		ORDER BY tag_id
+		ON CONFLICT (tag_id) DO UPDATE
+		SET article_count = tag_stats.article_count + EXCLUDED.article_count
+	`, map[string]interface{}{"old": oldVersionID, "new": newVersionID}).Error
+	if err != nil {
+		return err
+	}
+
+	return r.db.Exec(`
+		INSERT INTO tag_pair_stats (tag_id_a, tag_id_b, article_count)
+		SELECT tag_id_a, tag_id_b, SUM(delta)
+		FROM (
+			SELECT avt1.tag_id AS tag_id_a, avt2.tag_id AS tag_id_b, -1 AS delta
+			FROM article_version_tags avt1
+			JOIN article_version_tags avt2 ON avt2.article_version_id = avt1.article_version_id AND avt1.tag_id < avt2.tag_id
+			WHERE avt1.article_version_id = @old
+			UNION ALL
+			SELECT avt1.tag_id, avt2.tag_id, 1
+			FROM article_version_tags avt1
+			JOIN article_version_tags avt2 ON avt2.article_version_id = avt1.article_version_id AND avt1.tag_id < avt2.tag_id
+			WHERE avt1.article_version_id = @new
+		) d
+		GROUP BY tag_id_a, tag_id_b
+		HAVING SUM(delta) <> 0
+		ORDER BY tag_id_a, tag_id_b
+		ON CONFLICT (tag_id_a, tag_id_b) DO UPDATE
+		SET article_count = tag_pair_stats.article_count + EXCLUDED.article_count
+	`, map[string]interface{}{"old": oldVersionID, "new": newVersionID}).Error
+}
+
+// RebuildTagStats menghitung ulang seluruh statistik tag dari awal. Tabel
+// dikunci dari update bertahap selama rebuild; update yang menunggu akan
+// diterapkan di atas hasil rebuild setelah commit.
+func (r *articleRepository) RebuildTagStats() error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("LOCK TABLE tag_stats, tag_pair_stats IN SHARE ROW EXCLUSIVE MODE").Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM tag_pair_stats").Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM tag_stats").Error; err != nil {
+			return err
+		}
+
+		err := tx.Exec(`
+			INSERT INTO tag_stats (tag_id, article_count)
+			SELECT avt.tag_id, COUNT(DISTINCT a.id)
+			FROM articles a
+			JOIN article_versions av ON av.id = a.latest_version_id
+			JOIN article_version_tags avt ON avt.article_version_id = av.id
+			WHERE a.deleted_at IS NULL
+			  AND av.deleted_at IS NULL
+			GROUP BY avt.tag_id
+		`).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Exec(`
+			INSERT INTO tag_pair_stats (tag_id_a, tag_id_b, article_count)
+			SELECT avt1.tag_id, avt2.tag_id, COUNT(DISTINCT a.id)
+			FROM articles a
+			JOIN article_versions av ON av.id = a.latest_version_id
+			JOIN article_version_tags avt1 ON avt1.article_version_id = av.id
+			JOIN article_version_tags avt2 ON avt2.article_version_id = av.id AND avt1.tag_id < avt2.tag_id
+			WHERE a.deleted_at IS NULL
+			  AND av.deleted_at IS NULL
+			GROUP BY avt1.tag_id, avt2.tag_id
+		`).Error
+	})
+}
+
+// GetTagStatFrequencies sama dengan GetTagFrequencies, tetapi membaca tag_stats
+func (r *articleRepository) GetTagStatFrequencies(tagNames []string) (map[string]int, error) {
+	result := make(map[string]int)
+	if len(tagNames) == 0 {
+		return result, nil
+	}
+
+	rows, err := r.db.Raw(`
+		SELECT t.name, s.article_count
+		FROM tag_stats s
+		JOIN tags t ON t.id = s.tag_id
+		WHERE t.name IN (?)
+		  AND t.deleted_at IS NULL
+		  AND s.article_count > 0
+	`, tagNames).Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var name string
+		var freq int
+		if err := rows.Scan(&name, &freq); err != nil {
+			return nil, err
+		}
+		result[name] = freq
+	}
+	return result, rows.Err()
+}
+
+// GetTagStatPairCoOccurrences sama dengan GetTagPairCoOccurrences (key "a|b"
+// dengan a < b), tetapi membaca tag_pair_stats
+func (r *articleRepository) GetTagStatPairCoOccurrences(tagNames []string) (map[string]int, error) {
+	result := make(map[string]int)
+	if len(tagNames) < 2 {
+		return result, nil
+	}
+
+	rows, err := r.db.Raw(`
+		SELECT LEAST(ta.name, tb.name), GREATEST(ta.name, tb.name), p.article_count
+		FROM tag_pair_stats p
+		JOIN tags ta ON ta.id = p.tag_id_a
+		JOIN tags tb ON tb.id = p.tag_id_b
+		WHERE ta.name IN (?)
+		  AND tb.name IN (?)
+		  AND ta.deleted_at IS NULL
+		  AND tb.deleted_at IS NULL
+		  AND p.article_count > 0
+	`, tagNames, tagNames).Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tag1, tag2 string
+		var freq int
+		if err := rows.Scan(&tag1, &tag2, &freq); err != nil {
+			return nil, err
+		}
+		result[tag1+"|"+tag2] = freq
+	}
+	return result, rows.Err()
+}
+
+// GetCoOccurringTags - ambil tag lain yang muncul bersama tagNames dari
+// tag_pair_stats, hasilnya map[kandidat][tag input] = jumlah artikel
+func (r *articleRepository) GetCoOccurringTags(tagNames []string) (map[string]map[string]int, error) {
+	result := make(map[string]map[string]int)
+	if len(tagNames) == 0 {
+		return result, nil
+	}
+
+	// Pasangan disimpan sekali (tag_id_a < tag_id_b), jadi tag input bisa di sisi manapun
+	rows, err := r.db.Raw(`
+		SELECT candidate.name, seed.name, p.article_count
+		FROM tag_pair_stats p
+		JOIN tags seed ON seed.id = p.tag_id_a
+		JOIN tags candidate ON candidate.id = p.tag_id_b
+		WHERE seed.name IN (@names)
+		  AND candidate.name NOT IN (@names)
+		  AND seed.deleted_at IS NULL
+		  AND candidate.deleted_at IS NULL
+		  AND p.article_count > 0
+		UNION ALL
+		SELECT candidate.name, seed.name, p.article_count
+		FROM tag_pair_stats p
+		JOIN tags seed ON seed.id = p.tag_id_b
+		JOIN tags candidate ON candidate.id = p.tag_id_a
+		WHERE seed.name IN (@names)
+		  AND candidate.name NOT IN (@names)
+		  AND seed.deleted_at IS NULL
+		  AND candidate.deleted_at IS NULL
+		  AND p.article_count > 0
+	`, map[string]interface{}{"names": tagNames}).Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var candidate, seed string
+		var freq int
+		if err := rows.Scan(&candidate, &seed, &freq); err != nil {
+			return nil, err
+		}
+		if result[candidate] == nil {
+			result[candidate] = make(map[string]int)
+		}
+		result[candidate][seed] = freq
+	}
+	return result, rows.Err()
+}
@@ -0,0 +1,52 @@
+package repositories
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// pgUniqueViolation adalah SQLSTATE Postgres untuk pelanggaran unique constraint
+const pgUniqueViolation = "23505"
+
+// Repositories berisi repository yang berbagi satu transaksi database.
+type Repositories struct {
+	Articles        ArticleRepository
+	Tags            TagRepository
+	ArticleVersions ArticleVersionRepository
+	Users           UserRepository
+}
+
+// UnitOfWork menjalankan beberapa operasi repository secara atomik.
+type UnitOfWork interface {
+	Do(fn func(repos Repositories) error) error
+}
+
+type unitOfWork struct {
+	db *gorm.DB
+}
+
+func NewUnitOfWork(db *gorm.DB) UnitOfWork {
+	return &unitOfWork{db: db}
+}
+
+// Do menjalankan fn di dalam satu transaksi. Transaksi di-commit jika fn
+// mengembalikan nil dan di-rollback jika fn mengembalikan error atau panic.
+func (u *unitOfWork) Do(fn func(repos Repositories) error) error {
+	return u.db.Transaction(func(tx *gorm.DB) error {
+		return fn(Repositories{
+			Articles:        NewArticleRepository(tx),
+			Tags:            NewTagRepository(tx),
+			ArticleVersions: NewArticleVersionRepository(tx),
+			Users:           NewUserRepository(tx),
+		})
+	})
+}
+
+// IsUniqueViolation mengecek apakah err berasal dari pelanggaran unique constraint,
+// misalnya dua request yang mengalokasikan nomor versi yang sama.
+func IsUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation
+}
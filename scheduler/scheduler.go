@@ -0,0 +1,71 @@
+package scheduler
+
+import (
+	"context"
+	"hash/fnv"
+	"log"
+	"time"
+
+	"cisdi-test-cms/repositories"
+)
+
+// Job is a periodic background task. Run receives the tick time.
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(now time.Time) error
+}
+
+// Scheduler runs registered jobs in-process. Every tick a job first takes a
+// Postgres advisory lock derived from its name, so when several replicas are
+// running only one of them executes the job at a time.
+type Scheduler struct {
+	lockRepo repositories.LockRepository
+	jobs     []Job
+}
+
+func NewScheduler(lockRepo repositories.LockRepository) *Scheduler {
+	return &Scheduler{lockRepo: lockRepo}
+}
+
+func (s *Scheduler) Register(job Job) {
+	s.jobs = append(s.jobs, job)
+}
+
+// Start launches one goroutine per job; they stop when ctx is cancelled.
+func (s *Scheduler) Start(ctx context.Context) {
+	for _, job := range s.jobs {
+		go s.loop(ctx, job)
+	}
+}
+
+func (s *Scheduler) loop(ctx context.Context, job Job) {
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	log.Printf("scheduler: job %s started (interval %s)", job.Name, job.Interval)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.runJob(job, now)
+		}
+	}
+}
+
+func (s *Scheduler) runJob(job Job, now time.Time) {
+	// Kalau lock tidak didapat berarti replica lain sedang menjalankan job ini
+	_, err := s.lockRepo.WithAdvisoryLock(lockKey(job.Name), func() error {
+		return job.Run(now)
+	})
+	if err != nil {
+		log.Printf("scheduler: job %s failed: %v", job.Name, err)
+	}
+}
+
+func lockKey(name string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte("cms-scheduler:" + name))
+	return int64(h.Sum64())
+}
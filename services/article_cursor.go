@@ -0,0 +1,50 @@
+package services
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"cisdi-test-cms/models"
+)
+
+// ErrInvalidCursor dikembalikan jika cursor rusak atau dibuat untuk sort_by /
+// sort_order yang berbeda dengan request
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// NextArticleCursor membuat cursor opaque untuk halaman setelah articles.
+// Kosong jika halaman tidak penuh, artinya tidak ada halaman berikutnya.
+func NextArticleCursor(params models.ArticleListParams, articles []models.Article) string {
+	if params.Limit <= 0 || len(articles) < params.Limit {
+		return ""
+	}
+	last := articles[len(articles)-1]
+	payload, err := json.Marshal(models.ArticleCursor{
+		SortBy:    params.SortBy,
+		SortOrder: strings.ToLower(params.SortOrder),
+		Value:     last.SortKey,
+		ID:        last.ID,
+	})
+	if err != nil {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString(payload)
+}
+
+// decodeArticleCursor membaca cursor dari NextArticleCursor dan memastikan
+// urutannya sama dengan params
+func decodeArticleCursor(params models.ArticleListParams) (*models.ArticleCursor, error) {
+	payload, err := base64.RawURLEncoding.DecodeString(params.Cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var cursor models.ArticleCursor
+	if err := json.Unmarshal(payload, &cursor); err != nil || cursor.ID == 0 {
+		return nil, ErrInvalidCursor
+	}
+	if cursor.SortBy != params.SortBy || !strings.EqualFold(cursor.SortOrder, params.SortOrder) {
+		return nil, ErrInvalidCursor
+	}
+	return &cursor, nil
+}
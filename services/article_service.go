@@ -3,9 +3,13 @@ package services
 import (
 	"errors"
 	"fmt"
+	"log"
 	"math"
+	"sort"
+	"strings"
 	"time"
 
+	"cisdi-test-cms/authz"
 	"cisdi-test-cms/models"
 	"cisdi-test-cms/repositories"
 
@@ -13,221 +17,355 @@ import (
 )
 
 type ArticleService interface {
-	CreateArticle(req models.CreateArticleRequest, userID uint) (*models.Article, error)
-	GetArticle(id uint, userID uint, isPublic bool) (*models.Article, error)
-	GetArticles(params models.ArticleListParams, userID uint, isPublic bool) ([]models.Article, int64, error)
-	DeleteArticle(id uint, userID uint) error
-	CreateArticleVersion(articleID uint, req models.CreateArticleVersionRequest, userID uint) (*models.ArticleVersion, error)
-	UpdateVersionStatus(articleID, versionID uint, status models.VersionStatus, userID uint) error
-	GetArticleVersions(articleID uint, userID uint) ([]models.ArticleVersion, error)
-	GetArticleVersion(articleID, versionID uint, userID uint) (*models.ArticleVersion, error)
+	CreateArticle(req models.CreateArticleRequest, user authz.User) (*models.Article, error)
+	GetArticle(id uint, user authz.User, isPublic bool) (*models.Article, error)
+	GetPublicArticleBySlug(slug string) (*models.Article, string, error)
+	GetArticles(params models.ArticleListParams, user authz.User, isPublic bool) ([]models.Article, int64, error)
+	UpdateArticle(id uint, req models.UpdateArticleRequest, user authz.User) (*models.Article, error)
+	GetArticleAudits(articleID uint, user authz.User) ([]models.ArticleAudit, error)
+	DeleteArticle(id uint, user authz.User) error
+	GetTrashedArticles(page, limit int, user authz.User) ([]models.TrashedArticle, int64, error)
+	RestoreArticle(id uint, user authz.User) (*models.Article, error)
+	PurgeArticle(id uint, user authz.User) error
+	PurgeTrashedBefore(cutoff time.Time) error
+	CreateArticleVersion(articleID uint, req models.CreateArticleVersionRequest, user authz.User) (*models.ArticleVersion, error)
+	UpdateVersionStatus(articleID, versionID uint, req models.UpdateVersionStatusRequest, user authz.User) error
+	GetArticleVersions(articleID uint, user authz.User) ([]models.ArticleVersion, error)
+	GetArticleVersion(articleID, versionID uint, user authz.User) (*models.ArticleVersion, error)
+	DiffVersions(articleID, versionID, againstID uint, user authz.User) (*models.VersionDiff, error)
+	RevertArticleVersion(articleID, versionID uint, req models.RevertArticleVersionRequest, user authz.User) (*models.ArticleVersion, error)
+	ScheduleVersion(articleID, versionID uint, req models.ScheduleVersionRequest, user authz.User) (*models.ArticleVersion, error)
+	CancelVersionSchedule(articleID, versionID uint, user authz.User) error
+	GetPendingSchedules(user authz.User) ([]models.ArticleVersion, error)
+	RunScheduledPublishes(now time.Time) error
+	RunScheduledUnpublishes(now time.Time) error
+	GetVersionReviews(articleID, versionID uint, user authz.User) ([]models.VersionReview, error)
+	ScoreTags(tagNames []string, user authz.User) (*models.TagRelationshipScore, error)
+	RescoreArticleVersions() (int, error)
 }
 
 type articleService struct {
 	articleRepo        repositories.ArticleRepository
 	tagRepo            repositories.TagRepository
 	articleVersionRepo repositories.ArticleVersionRepository
+	uow                repositories.UnitOfWork
+	renderer           ContentRenderer
+	tagNormalizer      *TagNormalizer
+	scorer             RelationshipScorer
+	listeners          []PublicationListener
 }
 
-func NewArticleService(articleRepo repositories.ArticleRepository, tagRepo repositories.TagRepository, articleVersionRepo repositories.ArticleVersionRepository) ArticleService {
+func NewArticleService(articleRepo repositories.ArticleRepository, tagRepo repositories.TagRepository, articleVersionRepo repositories.ArticleVersionRepository, uow repositories.UnitOfWork, renderer ContentRenderer, tagNormalizer *TagNormalizer, scorer RelationshipScorer, listeners ...PublicationListener) ArticleService {
 	return &articleService{
 		articleRepo:        articleRepo,
 		tagRepo:            tagRepo,
 		articleVersionRepo: articleVersionRepo,
+		uow:                uow,
+		renderer:           renderer,
+		tagNormalizer:      tagNormalizer,
+		scorer:             scorer,
+		listeners:          listeners,
 	}
 }
 
-func (s *articleService) CreateArticle(req models.CreateArticleRequest, userID uint) (*models.Article, error) {
-	// Process tags save new tags if they don't exist
-	tags, err := s.processTagsForVersion(req.Tags)
-	if err != nil {
-		return nil, err
+func (s *articleService) CreateArticle(req models.CreateArticleRequest, user authz.User) (*models.Article, error) {
+	if !authz.Can(user, authz.ActionCreate, nil) {
+		return nil, authz.ErrUnauthorized
 	}
 
-	// Create article
-	article := &models.Article{
-		AuthorID: userID,
-		Title:    req.Title,
-	}
+	var article *models.Article
+	var version *models.ArticleVersion
 
-	// Create first version
-	version := &models.ArticleVersion{
-		VersionNumber: 1,
-		Title:         req.Title,
-		Content:       req.Content,
-		Status:        models.StatusDraft,
-		Tags:          tags,
-	}
+	// Artikel, versi pertama dan tag baru dibuat atomik; jika bentrok dengan
+	// request lain (slug atau nama tag yang sama) seluruh transaksi diulang
+	err := retryOnConflict(func() error {
+		return s.uow.Do(func(repos repositories.Repositories) error {
+			// Process tags save new tags if they don't exist
+			tags, err := processTagsForVersion(repos.Tags, s.tagNormalizer, req.Tags)
+			if err != nil {
+				return err
+			}
 
-	// Create article first, then version
-	if _, err := s.articleRepo.Create(article); err != nil {
-		return nil, err
-	}
+			slug, err := uniqueSlug(repos.Articles, req.Title, 0)
+			if err != nil {
+				return err
+			}
 
-	version.ArticleID = article.ID
-	if err := s.articleRepo.CreateVersion(version); err != nil {
-		return nil, err
-	}
+			// Create article
+			article = &models.Article{
+				AuthorID: user.ID,
+				Title:    req.Title,
+				Slug:     slug,
+			}
 
-	// Update article with version ID
-	article.LatestVersionID = version.ID
-	if err := s.articleRepo.Update(article); err != nil {
-		return nil, err
-	}
+			// Create first version
+			version = &models.ArticleVersion{
+				VersionNumber: 1,
+				Title:         req.Title,
+				Content:       req.Content,
+				ContentFormat: contentFormatOrDefault(req.ContentFormat),
+				Status:        models.StatusDraft,
+				Tags:          tags,
+			}
 
-	// Calculate article tag relationship score
-	fmt.Println("Calculating tag relationship score for article ID:", article.ID)
-	score := s.CalculateTagRelationshipScore(int(article.ID))
-	fmt.Println("Calculated score:", score)
+			// Create article first, then version
+			if _, err := repos.Articles.Create(article); err != nil {
+				return err
+			}
 
-	// Update article with tag relationship score
-	err = s.articleRepo.UpdateVersion(version.ID, map[string]interface{}{
-		"article_tag_relationship_score": score,
+			version.ArticleID = article.ID
+			if err := repos.Articles.CreateVersion(version); err != nil {
+				return err
+			}
+
+			// Update article with version ID
+			if err := repos.Articles.UpdateFields(article.ID, map[string]interface{}{
+				"latest_version_id": version.ID,
+			}); err != nil {
+				return err
+			}
+			return repos.Articles.UpdateTagStats(0, version.ID)
+		})
 	})
 	if err != nil {
 		return nil, err
 	}
 
+	// Score dihitung dari tag versi pertama setelah statistik tag ikut ter-update
+	if err := s.storeRelationshipScore(version); err != nil {
+		return nil, err
+	}
+
 	// Load the complete article
 	return s.articleRepo.GetByID(article.ID)
 }
 
-func (s *articleService) GetArticle(id uint, userID uint, isPublic bool) (*models.Article, error) {
+func (s *articleService) GetArticle(id uint, user authz.User, isPublic bool) (*models.Article, error) {
 	article, err := s.articleRepo.GetByID(id)
 	if err != nil {
 		return nil, err
 	}
 
 	// Check access permissions
-	if isPublic && (article.PublishedVersion == nil || article.PublishedVersion.Status != models.StatusPublished) {
+	if isPublic && !isPubliclyVisible(article) {
 		return nil, errors.New("article not found")
 	}
 
-	if !isPublic && article.AuthorID != userID {
-		// Allow editors and admins to view any article
-		// This would need role checking in the handler
+	// Editor dan admin boleh melihat semua artikel, writer hanya miliknya sendiri
+	if !isPublic && !authz.Can(user, authz.ActionView, article) {
+		return nil, authz.ErrUnauthorized
+	}
+
+	if isPublic {
+		s.renderPublishedContent(article)
 	}
 
 	return article, nil
 }
 
-func (s *articleService) GetArticles(params models.ArticleListParams, userID uint, isPublic bool) ([]models.Article, int64, error) {
-	return s.articleRepo.GetList(params, isPublic)
-}
+func (s *articleService) GetArticles(params models.ArticleListParams, user authz.User, isPublic bool) ([]models.Article, int64, error) {
+	// Jika tidak boleh melihat semua artikel, status selain published hanya milik sendiri
+	if !isPublic && !authz.Can(user, authz.ActionView, nil) && params.Status != string(models.StatusPublished) {
+		params.AuthorID = user.ID
+	}
 
-func (s *articleService) DeleteArticle(id uint, userID uint) error {
-	article, err := s.articleRepo.GetByID(id)
+	if params.TagID > 0 && params.IncludeDescendants {
+		tagIDs, err := s.tagRepo.GetDescendantIDs(params.TagID)
+		if err != nil {
+			return nil, 0, err
+		}
+		params.TagIDs = tagIDs
+	}
+
+	params.After = nil
+	if params.Cursor != "" {
+		after, err := decodeArticleCursor(params)
+		if err != nil {
+			return nil, 0, err
+		}
+		params.After = after
+	}
+
+	articles, total, err := s.articleRepo.GetList(params, isPublic)
 	if err != nil {
-		return err
+		return nil, 0, err
 	}
 
-	// Check ownership or admin/editor role (would need role in context)
-	if article.AuthorID != userID {
-		return errors.New("unauthorized")
+	if isPublic {
+		for i := range articles {
+			s.renderPublishedContent(&articles[i])
+		}
 	}
 
-	// Delete article versions first
-	if err := s.articleVersionRepo.DeleteVersionsByArticleID(id); err != nil {
-		return err
+	return articles, total, nil
+}
+
+// renderPublishedContent mengisi content_html versi published untuk response publik.
+func (s *articleService) renderPublishedContent(article *models.Article) {
+	if article.PublishedVersion != nil {
+		article.PublishedVersion.ContentHTML = s.renderer.Render(article.PublishedVersion)
 	}
+}
 
-	return s.articleRepo.Delete(id)
+func contentFormatOrDefault(format models.ContentFormat) models.ContentFormat {
+	if format == "" {
+		return models.ContentFormatHTML
+	}
+	return format
 }
 
-func (s *articleService) CreateArticleVersion(articleID uint, req models.CreateArticleVersionRequest, userID uint) (*models.ArticleVersion, error) {
-	// Check if article exists and user has access
-	article, err := s.articleRepo.GetByID(articleID)
+func (s *articleService) DeleteArticle(id uint, user authz.User) error {
+	article, err := s.articleRepo.GetByID(id)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	if article.AuthorID != userID {
-		return nil, errors.New("unauthorized")
+	// Writer dan editor hanya boleh menghapus artikel sendiri, admin semua artikel
+	if !authz.Can(user, authz.ActionDelete, article) {
+		return authz.ErrUnauthorized
 	}
 
-	// Get existing versions to determine next version number
-	versions, err := s.articleRepo.GetVersions(articleID)
+	// Versi dan artikel dihapus atomik
+	err = s.uow.Do(func(repos repositories.Repositories) error {
+		locked, err := repos.Articles.LockForUpdate(id)
+		if err != nil {
+			return err
+		}
+		if err := repos.ArticleVersions.DeleteVersionsByArticleID(id); err != nil {
+			return err
+		}
+		if err := repos.Articles.Delete(id); err != nil {
+			return err
+		}
+		return repos.Articles.UpdateTagStats(locked.LatestVersionID, 0)
+	})
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	nextVersionNumber := 1
-	if len(versions) > 0 {
-		nextVersionNumber = versions[0].VersionNumber + 1
+	if article.PublishedVersionID != nil {
+		s.notifyPublicationChanged()
 	}
+	return nil
+}
 
-	// Process tags
-	tags, err := s.processTagsForVersion(req.Tags)
+func (s *articleService) CreateArticleVersion(articleID uint, req models.CreateArticleVersionRequest, user authz.User) (*models.ArticleVersion, error) {
+	// Check if article exists and user has access
+	article, err := s.articleRepo.GetByID(articleID)
 	if err != nil {
 		return nil, err
 	}
 
-	// Create new version
-	version := &models.ArticleVersion{
-		ArticleID:     articleID,
-		VersionNumber: nextVersionNumber,
-		Title:         req.Title,
-		Content:       req.Content,
-		Status:        models.StatusDraft,
-		Tags:          tags,
+	if !authz.Can(user, authz.ActionEdit, article) {
+		return nil, authz.ErrUnauthorized
 	}
 
-	articleIDint := int(articleID)
+	precondition := func(repos repositories.Repositories, locked *models.Article) error {
+		return checkVersionPrecondition(repos.Articles, locked, req.IfMatch, req.BaseVersionID, true)
+	}
 
-	// Calculate article tag relationship score
-	version.ArticleTagRelationshipScore = s.CalculateTagRelationshipScore(articleIDint)
+	version, err := s.createNextVersion(articleID, precondition, func(repos repositories.Repositories) (*models.ArticleVersion, error) {
+		// Process tags
+		tags, err := processTagsForVersion(repos.Tags, s.tagNormalizer, req.Tags)
+		if err != nil {
+			return nil, err
+		}
 
-	if err := s.articleRepo.CreateVersion(version); err != nil {
+		return &models.ArticleVersion{
+			Title:         req.Title,
+			Content:       req.Content,
+			ContentFormat: contentFormatOrDefault(req.ContentFormat),
+			Status:        models.StatusDraft,
+			Tags:          tags,
+		}, nil
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	// Update article's latest version
-	if err := s.articleRepo.UpdateFields(articleID, map[string]interface{}{
-		"latest_version_id": version.ID,
-	}); err != nil {
+	// Score dihitung dari tag versi ini setelah statistik tag ikut ter-update
+	if err := s.storeRelationshipScore(version); err != nil {
 		return nil, err
 	}
 
-	// Update tag usage counts
-	s.updateTagUsageCounts()
-
 	return s.articleRepo.GetVersionByID(version.ID)
 }
 
-func (s *articleService) UpdateVersionStatus(articleID, versionID uint, status models.VersionStatus, userID uint) error {
-	fmt.Println("Updating version status: v1 ", versionID, "to", status, " for article", articleID)
-	// Check article access
-	article, err := s.articleRepo.GetByID(articleID)
+func (s *articleService) UpdateVersionStatus(articleID, versionID uint, req models.UpdateVersionStatusRequest, user authz.User) error {
+	// Precondition, workflow dan perubahan status dicek dan dijalankan terhadap
+	// artikel yang terkunci, sehingga dua request dengan ETag yang sama tidak
+	// bisa sama-sama lolos
+	var publicationChanged bool
+	err := s.uow.Do(func(repos repositories.Repositories) error {
+		locked, err := repos.Articles.LockForUpdate(articleID)
+		if err != nil {
+			return err
+		}
+		// Check article access, editor dan admin boleh me-review artikel penulis lain
+		if !authz.Can(user, authz.ActionChangeStatus, locked) {
+			return authz.ErrUnauthorized
+		}
+
+		if err := checkVersionPrecondition(repos.Articles, locked, req.IfMatch, req.BaseVersionID, true); err != nil {
+			return err
+		}
+
+		// Get the version
+		version, err := repos.Articles.GetVersion(articleID, versionID)
+		if err != nil {
+			return err
+		}
+
+		// Enforce review workflow
+		fromStatus := version.Status
+		if err := checkVersionTransition(fromStatus, req.Status, authz.Can(user, authz.ActionReview, locked), req.Comment); err != nil {
+			return err
+		}
+
+		publicationChanged, err = s.applyVersionStatus(repos, locked, version, req.Status, user.ID)
+		if err != nil {
+			return err
+		}
+
+		return repos.ArticleVersions.CreateReview(&models.VersionReview{
+			ArticleVersionID: version.ID,
+			ReviewerID:       user.ID,
+			FromStatus:       fromStatus,
+			ToStatus:         req.Status,
+			Comment:          strings.TrimSpace(req.Comment),
+		})
+	})
 	if err != nil {
 		return err
 	}
-	if article.AuthorID != userID {
-		return errors.New("unauthorized")
-	}
 
-	// Get the version
-	version, err := s.articleRepo.GetVersion(articleID, versionID)
-	if err != nil {
-		return err
+	if publicationChanged {
+		s.notifyPublicationChanged()
 	}
+	return nil
+}
+
+// applyVersionStatus menjalankan perubahan status tanpa cek akses, dipakai oleh
+// UpdateVersionStatus dan job penjadwalan publish/unpublish (actorID 0). Harus
+// dipanggil di dalam unit of work dengan article hasil LockForUpdate, sehingga
+// arsip versi lama, published_version_id, judul/slug dan status versi berubah
+// atomik. Mengembalikan true jika versi published artikel berubah; listener
+// publikasi dipanggil pemanggil setelah transaksi commit.
+func (s *articleService) applyVersionStatus(repos repositories.Repositories, article *models.Article, version *models.ArticleVersion, status models.VersionStatus, actorID uint) (bool, error) {
+	wasPublished := article.PublishedVersionID != nil && *article.PublishedVersionID == version.ID
 
 	// Handle status changes
 	if status == models.StatusPublished {
 		// If publishing this version, unpublish any currently published version
 		if article.PublishedVersionID != nil && *article.PublishedVersionID != version.ID {
-			currentPublished, err := s.articleRepo.GetVersionByID(*article.PublishedVersionID)
-			if err != nil {
-				return fmt.Errorf("failed to get current published version: %w", err)
-			} else {
-				if err = s.articleRepo.UpdateVersion(
-					currentPublished.ID,
-					map[string]interface{}{
-						"status": models.StatusArchivedVersion,
-					},
-				); err != nil {
-					return fmt.Errorf("failed to archive current published version: %w", err)
-				}
-				// 10 updated to archived
+			if err := repos.Articles.UpdateVersion(
+				*article.PublishedVersionID,
+				map[string]interface{}{
+					"status":       models.StatusArchivedVersion,
+					"unpublish_at": nil,
+				},
+			); err != nil {
+				return false, fmt.Errorf("failed to archive current published version: %w", err)
 			}
 		}
 
@@ -238,321 +376,393 @@ func (s *articleService) UpdateVersionStatus(articleID, versionID uint, status m
 
 		// Update article's published version
 		articleFields := map[string]interface{}{
-			"published_version_id": versionID,
+			"published_version_id": version.ID,
 		}
-		if err := s.articleRepo.UpdateFields(articleID, articleFields); err != nil {
-			return fmt.Errorf("failed to update article fields: %w", err)
+		if err := repos.Articles.UpdateFields(article.ID, articleFields); err != nil {
+			return false, fmt.Errorf("failed to update article fields: %w", err)
 		}
+		article.PublishedVersionID = &version.ID
 
-	} else if status == models.StatusArchivedVersion {
-		// If archiving the currently published version
-		if article.PublishedVersionID != nil && *article.PublishedVersionID == version.ID {
-			// This is unpublishing scenario - no published version anymore
-			if err := s.articleRepo.ClearPublishedVersionID(article.ID); err != nil {
-				return fmt.Errorf("failed to clear published version: %w", err)
-			}
+		// Setiap publish dicatat di bucket harian untuk trending per window
+		if err := repos.Tags.RecordDailyUsage(version.ID, now); err != nil {
+			return false, fmt.Errorf("failed to record tag usage: %w", err)
 		}
 
-		version.Status = status
-		// Clear published date when archiving
-		version.PublishedAt = nil
+		// Judul dan URL publik mengikuti judul versi yang dipublikasikan
+		if err := syncArticleTitle(repos.Articles, article, version.Title, actorID); err != nil {
+			return false, err
+		}
+		if err := syncArticleSlug(repos.Articles, article, version.Title); err != nil {
+			return false, err
+		}
 
 	} else {
-		// Handle other status changes (draft, etc.)
 		version.Status = status
+		if status == models.StatusArchivedVersion {
+			// Clear published date when archiving
+			version.PublishedAt = nil
+		}
 
-		// If this version was published and now changing to draft, clear article's published reference
-		if article.PublishedVersionID != nil && *article.PublishedVersionID == version.ID {
-			article.PublishedVersionID = nil
-			if err := s.articleRepo.Update(article); err != nil {
-				return fmt.Errorf("failed to clear published version reference: %w", err)
+		// Versi yang sedang published diarsipkan atau dikembalikan ke draft:
+		// artikel tidak punya versi published lagi
+		if wasPublished {
+			if err := repos.Articles.ClearPublishedVersionID(article.ID); err != nil {
+				return false, fmt.Errorf("failed to clear published version: %w", err)
 			}
+			article.PublishedVersionID = nil
 		}
 	}
 
 	// Update the version
-	if err := s.articleRepo.UpdateVersion(versionID, map[string]interface{}{
+	updates := map[string]interface{}{
 		"status":       version.Status,
 		"published_at": version.PublishedAt,
+	}
+	// Jadwal publish selalu dihapus: versi yang sudah published tidak perlu
+	// dipublish lagi, dan status lain harus dijadwalkan ulang lewat
+	// ScheduleVersion agar workflow review dicek kembali. Jadwal unpublish hanya
+	// relevan untuk versi published.
+	updates["publish_at"] = nil
+	if version.Status != models.StatusPublished {
+		updates["unpublish_at"] = nil
+	}
+	if err := repos.Articles.UpdateVersion(version.ID, updates); err != nil {
+		return false, fmt.Errorf("failed to update version: %w", err)
+	}
+
+	// Setiap perubahan status menggeser updated_at artikel agar ETag ikut berubah
+	if err := repos.Articles.UpdateFields(article.ID, map[string]interface{}{
+		"updated_at": time.Now(),
 	}); err != nil {
-		return fmt.Errorf("failed to update version: %w", err)
+		return false, fmt.Errorf("failed to touch article: %w", err)
 	}
 
-	// Update tag usage counts after status change
-	s.updateTagUsageCounts()
+	return status == models.StatusPublished || wasPublished, nil
+}
 
-	return nil
+func (s *articleService) notifyPublicationChanged() {
+	for _, listener := range s.listeners {
+		listener.PublicationChanged()
+	}
 }
 
-func (s *articleService) GetArticleVersions(articleID uint, userID uint) ([]models.ArticleVersion, error) {
+func (s *articleService) GetArticleVersions(articleID uint, user authz.User) ([]models.ArticleVersion, error) {
 	// Check access
 	article, err := s.articleRepo.GetByID(articleID)
 	if err != nil {
 		return nil, err
 	}
 
-	if article.AuthorID != userID {
-		return nil, errors.New("unauthorized")
+	if !authz.Can(user, authz.ActionView, article) {
+		return nil, authz.ErrUnauthorized
 	}
 
 	return s.articleRepo.GetVersions(articleID)
 }
 
-func (s *articleService) GetArticleVersion(articleID, versionID uint, userID uint) (*models.ArticleVersion, error) {
+func (s *articleService) GetArticleVersion(articleID, versionID uint, user authz.User) (*models.ArticleVersion, error) {
 	// Check access
 	article, err := s.articleRepo.GetByID(articleID)
 	if err != nil {
 		return nil, err
 	}
 
-	if article.AuthorID != userID {
-		return nil, errors.New("unauthorized")
+	if !authz.Can(user, authz.ActionView, article) {
+		return nil, authz.ErrUnauthorized
 	}
 
 	return s.articleRepo.GetVersion(articleID, versionID)
 }
 
-func (s *articleService) processTagsForVersion(tagNames []string) ([]models.Tag, error) {
-	var tags []models.Tag
-
-	for _, name := range tagNames {
-		tag, err := s.tagRepo.GetByName(name)
-		if err != nil {
-			if errors.Is(err, gorm.ErrRecordNotFound) {
-				// Create new tag
-				newTag := &models.Tag{
-					Name:          name,
-					UsageCount:    0,
-					TrendingScore: 0,
-				}
-				if err := s.tagRepo.Create(newTag); err != nil {
-					return nil, err
-				}
-				tags = append(tags, *newTag)
-			} else {
-				return nil, err
-			}
-		} else {
-			tags = append(tags, *tag)
-		}
+// RevertArticleVersion clones the title, content and tags of an older version
+// into a new draft, which becomes the article's latest version.
+func (s *articleService) RevertArticleVersion(articleID, versionID uint, req models.RevertArticleVersionRequest, user authz.User) (*models.ArticleVersion, error) {
+	article, err := s.articleRepo.GetByID(articleID)
+	if err != nil {
+		return nil, err
 	}
 
-	return tags, nil
-}
+	if !authz.Can(user, authz.ActionEdit, article) {
+		return nil, authz.ErrUnauthorized
+	}
 
-func (s *articleService) CalculateTagRelationshipScore(articleID int) float64 {
-	// 1. Ambil semua tag dari artikel ini
-	tags, err := s.articleRepo.GetTagsForArticle(articleID)
+	source, err := s.articleRepo.GetVersion(articleID, versionID)
 	if err != nil {
-		fmt.Println("Error getting tags for article:", err)
-		return 0.0
-	}
-	if len(tags) < 2 {
-		return 0.0
+		return nil, err
 	}
-	fmt.Println("Tags for article:", tags)
 
-	// 2. Ambil total artikel
-	totalArticles, err := s.articleRepo.GetTotalArticleCount()
-	if err != nil {
-		fmt.Println("Error getting total article count:", err)
-		return 0.0
+	precondition := func(repos repositories.Repositories, locked *models.Article) error {
+		return checkVersionPrecondition(repos.Articles, locked, req.IfMatch, req.BaseVersionID, true)
 	}
-	totalArticlesF := float64(totalArticles)
-	fmt.Printf("Total articles: %d (float: %.0f)\n", totalArticles, totalArticlesF)
 
-	// 3. Ambil frekuensi semua tag
-	tagFreq, err := s.articleRepo.GetTagFrequencies(tags)
+	revertedFrom := source.ID
+	version, err := s.createNextVersion(articleID, precondition, func(repos repositories.Repositories) (*models.ArticleVersion, error) {
+		return &models.ArticleVersion{
+			Title:                 source.Title,
+			Content:               source.Content,
+			ContentFormat:         source.ContentFormat,
+			Status:                models.StatusDraft,
+			Tags:                  source.Tags,
+			RevertedFromVersionID: &revertedFrom,
+		}, nil
+	})
 	if err != nil {
-		fmt.Println("Error getting tag frequencies:", err)
-		return 0.0
+		return nil, err
 	}
-	fmt.Println("Tag frequencies:", tagFreq)
 
-	// 4. Ambil co-occurrence semua pasangan tag
-	coOccurMap, err := s.articleRepo.GetTagPairCoOccurrences(tags)
-	if err != nil {
-		fmt.Println("Error getting tag pair co-occurrences:", err)
-		return 0.0
+	// Score dihitung dari tag versi ini setelah statistik tag ikut ter-update
+	if err := s.storeRelationshipScore(version); err != nil {
+		return nil, err
 	}
-	fmt.Println("Co-occurrence map:", coOccurMap)
 
-	// 5. Hitung skor
-	scoreSum := 0.0
-	pairCount := 0
+	return s.articleRepo.GetVersionByID(version.ID)
+}
 
-	// helper untuk urutkan tag sesuai LEAST/GREATEST
-	minString := func(a, b string) string {
-		if a < b {
-			return a
-		}
-		return b
-	}
-	maxString := func(a, b string) string {
-		if a > b {
-			return a
-		}
-		return b
-	}
+// createNextVersion menyimpan versi hasil build dengan nomor versi berikutnya dan
+// menjadikannya latest version dalam satu transaksi. Baris artikel dikunci selama
+// alokasi dan precondition dicek terhadap artikel yang sudah terkunci; jika tetap
+// bentrok dengan unique (article_id, version_number), seluruh transaksi diulang
+// dan build dipanggil lagi.
+func (s *articleService) createNextVersion(articleID uint, precondition func(repos repositories.Repositories, article *models.Article) error, build func(repos repositories.Repositories) (*models.ArticleVersion, error)) (*models.ArticleVersion, error) {
+	var version *models.ArticleVersion
+
+	err := retryOnConflict(func() error {
+		return s.uow.Do(func(repos repositories.Repositories) error {
+			locked, err := repos.Articles.LockForUpdate(articleID)
+			if err != nil {
+				return err
+			}
+			if err := precondition(repos, locked); err != nil {
+				return err
+			}
 
-	for i := 0; i < len(tags)-1; i++ {
-		for j := i + 1; j < len(tags); j++ {
-			tag1 := tags[i]
-			tag2 := tags[j]
+			latest, err := repos.Articles.GetLatestVersionNumber(articleID)
+			if err != nil {
+				return err
+			}
 
-			// Pastikan key cocok
-			key := fmt.Sprintf("%s|%s", minString(tag1, tag2), maxString(tag1, tag2))
-			freqA := float64(tagFreq[tag1])
-			freqB := float64(tagFreq[tag2])
-			coOccur := float64(coOccurMap[key])
+			version, err = build(repos)
+			if err != nil {
+				return err
+			}
+			version.ArticleID = articleID
+			version.VersionNumber = latest + 1
 
-			// Debug semua data
-			fmt.Printf("Pair: %-10s & %-10s | freqA=%-4.0f freqB=%-4.0f coOccur=%-4.0f\n",
-				tag1, tag2, freqA, freqB, coOccur)
+			if err := repos.Articles.CreateVersion(version); err != nil {
+				return err
+			}
 
-			if freqA == 0 || freqB == 0 || coOccur == 0 {
-				continue
+			// Update article's latest version
+			if err := repos.Articles.UpdateFields(articleID, map[string]interface{}{
+				"latest_version_id": version.ID,
+			}); err != nil {
+				return err
 			}
+			return repos.Articles.UpdateTagStats(locked.LatestVersionID, version.ID)
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
 
-			pTag1 := freqA / totalArticlesF
-			pTag2 := freqB / totalArticlesF
-			pBoth := coOccur / totalArticlesF
+	return version, nil
+}
 
-			pmi := math.Log(pBoth / (pTag1 * pTag2))
+func processTagsForVersion(tagRepo repositories.TagRepository, normalizer *TagNormalizer, tagNames []string) ([]models.Tag, error) {
+	var tags []models.Tag
+	seen := make(map[uint]bool)
 
-			// Kalau mau Positive PMI aktifkan ini:
-			// if pmi < 0 { pmi = 0 }
+	for _, rawName := range tagNames {
+		name, err := normalizer.Normalize(rawName)
+		if err != nil {
+			return nil, err
+		}
 
-			fmt.Printf("  -> pTag1=%.4f pTag2=%.4f pBoth=%.4f PMI=%.4f\n", pTag1, pTag2, pBoth, pmi)
+		tag, err := resolveTag(tagRepo, name)
+		if err != nil {
+			return nil, err
+		}
 
-			scoreSum += pmi
-			pairCount++
+		// Nama berbeda bisa mengarah ke tag kanonik yang sama (alias)
+		if seen[tag.ID] {
+			continue
 		}
+		seen[tag.ID] = true
+		tags = append(tags, *tag)
 	}
 
-	if pairCount == 0 {
-		return 0.0
-	}
-	fmt.Printf("Pair count: %d | Score sum: %.4f | Final score: %.4f\n", pairCount, scoreSum, scoreSum/float64(pairCount))
-	return scoreSum / float64(pairCount)
+	return tags, nil
 }
 
-// Fungsi utama: hitung skor hubungan antar tag
-func (s *articleService) calculateArticleTagRelationshipScoreCreateArticleVersion(articleID int) float64 {
-	tags, err := s.articleRepo.GetTagsForArticle(articleID)
-	if err != nil {
-		fmt.Println("Error getting tags for article:", err)
-		return 0.0
+// resolveTag mencari tag kanonik untuk nama yang sudah dinormalisasi: alias lebih dulu, lalu nama
+// tag. Tag yang pernah dihapus dipulihkan, dan nama yang belum dikenal dibuat
+// sebagai tag baru.
+func resolveTag(tagRepo repositories.TagRepository, name string) (*models.Tag, error) {
+	tag, err := tagRepo.GetByAlias(name)
+	if err == nil {
+		return tag, nil
 	}
-	if len(tags) < 2 {
-		return 0.0
-	}
-	for i, t := range tags {
-		fmt.Printf("Tag %d: %s\n", i+1, t)
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
 	}
 
-	countArticles, err := s.articleRepo.GetTotalArticleCount()
-	if err != nil {
-		fmt.Println("Error getting total article count:", err)
-		return 0.0
+	tag, err = tagRepo.GetByNameUnscoped(name)
+	if err == nil {
+		if tag.DeletedAt.Valid {
+			if err := tagRepo.Restore(tag.ID); err != nil {
+				return nil, err
+			}
+			tag.DeletedAt = gorm.DeletedAt{}
+		}
+		return tag, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
 	}
 
-	totalArticles := float64(countArticles)
-	scoreSum := 0.0
-	pairCount := 0
-
-	for i := 0; i < len(tags)-1; i++ {
-		for j := i + 1; j < len(tags); j++ {
-			tag1 := tags[i]
-			tag2 := tags[j]
-			countTag1Int, err := s.articleRepo.GetArticleCountWithTag(tag1)
-			if err != nil {
-				fmt.Println("Error getting count for tag:", tag1, err)
-				continue
-			}
-			countTag2Int, err := s.articleRepo.GetArticleCountWithTag(tag2)
-			if err != nil {
-				fmt.Println("Error getting count for tag:", tag2, err)
-				continue
-			}
-			countBothInt, err := s.articleRepo.GetArticleCountWithTags(tag1, tag2)
-			if err != nil {
-				fmt.Println("Error getting count for tag pair:", tag1, tag2, err)
-				continue
-			}
+	// Create new tag
+	newTag := &models.Tag{
+		Name:          name,
+		UsageCount:    0,
+		TrendingScore: 0,
+	}
+	if err := tagRepo.Create(newTag); err != nil {
+		return nil, err
+	}
+	return newTag, nil
+}
 
-			countTag1 := float64(countTag1Int)
-			countTag2 := float64(countTag2Int)
-			countBoth := float64(countBothInt)
+// ScoreTags menghitung article_tag_relationship_score untuk daftar tag
+// kandidat tanpa menyimpan apapun, misalnya untuk draft yang belum disimpan.
+// Nama tag dinormalisasi dan alias diarahkan ke tag kanoniknya seperti saat
+// versi dibuat, tetapi tag yang belum ada tidak dibuat (pasangannya tidak dinilai).
+func (s *articleService) ScoreTags(tagNames []string, user authz.User) (*models.TagRelationshipScore, error) {
+	if !authz.Can(user, authz.ActionCreate, nil) {
+		return nil, authz.ErrUnauthorized
+	}
 
-			if countTag1 == 0 || countTag2 == 0 || countBoth == 0 {
-				continue
-			}
+	names, err := s.canonicalTagNames(tagNames)
+	if err != nil {
+		return nil, err
+	}
+	return s.tagRelationshipScore(names)
+}
 
-			pTag1 := countTag1 / totalArticles
-			pTag2 := countTag2 / totalArticles
-			pBoth := countBoth / totalArticles
+// canonicalTagNames menormalisasi nama tag dan mengganti alias dengan nama tag
+// kanoniknya, tanpa duplikat dan urut nama
+func (s *articleService) canonicalTagNames(rawNames []string) ([]string, error) {
+	names := make([]string, 0, len(rawNames))
+	seen := make(map[string]bool, len(rawNames))
+	for _, rawName := range rawNames {
+		name, err := s.tagNormalizer.Normalize(rawName)
+		if err != nil {
+			return nil, err
+		}
 
-			pmi := math.Log(pBoth / (pTag1 * pTag2))
-			scoreSum += pmi
-			pairCount++
+		tag, err := s.tagRepo.GetByAlias(name)
+		if err == nil {
+			name = tag.Name
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
 		}
-	}
 
-	if pairCount == 0 {
-		return 0.0
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
 	}
 
-	averageScore := scoreSum / float64(pairCount)
-	return averageScore
+	sort.Strings(names)
+	return names, nil
 }
 
-func (s *articleService) updateTagUsageCounts() {
-	// Ambil usage count dari artikel published
-	tagCounts, err := s.articleRepo.CountArticlesByTag()
-	if err != nil {
-		return
+// tagRelationshipScore menilai semua pasangan tags dengan scorer yang
+// dikonfigurasi (RELATIONSHIP_SCORER) terhadap tag_stats dan tag_pair_stats
+func (s *articleService) tagRelationshipScore(tags []string) (*models.TagRelationshipScore, error) {
+	result := &models.TagRelationshipScore{
+		Scorer: s.scorer.Name(),
+		Tags:   tags,
+		Pairs:  []models.TagPairScore{},
+	}
+	if len(tags) < 2 {
+		return result, nil
 	}
 
-	// Ambil semua tag
-	allTags, err := s.tagRepo.GetAll()
+	totalArticles, err := s.articleRepo.GetTotalArticleCount()
 	if err != nil {
-		return
+		return nil, err
+	}
+	tagFreq, err := s.articleRepo.GetTagStatFrequencies(tags)
+	if err != nil {
+		return nil, err
+	}
+	coOccurMap, err := s.articleRepo.GetTagStatPairCoOccurrences(tags)
+	if err != nil {
+		return nil, err
 	}
 
-	const decayFactor = 7.0
-	var tagsToUpdate []models.Tag
+	result.TotalArticles = totalArticles
+	result.Score, result.Pairs = scoreTagPairs(s.scorer, tags, tagFreq, coOccurMap, totalArticles)
+	return result, nil
+}
 
-	for i := range allTags {
-		currentTag := &allTags[i]
+// storeRelationshipScore menghitung skor dari tag versi itu sendiri (bukan
+// dari latest version artikel) lalu menyimpannya. Dipanggil setelah transaksi
+// commit sehingga statistik sudah memuat versi ini; error statistik hanya di-log
+// dan skor dianggap 0 agar versi yang sudah tersimpan tidak gagal dibuat.
+func (s *articleService) storeRelationshipScore(version *models.ArticleVersion) error {
+	names := make([]string, 0, len(version.Tags))
+	for _, tag := range version.Tags {
+		names = append(names, tag.Name)
+	}
+	sort.Strings(names)
 
-		// Hitung usage count baru
-		newCount := 0
-		if count, exists := tagCounts[currentTag.ID]; exists {
-			newCount = count
-		}
+	score := 0.0
+	result, err := s.tagRelationshipScore(names)
+	if err != nil {
+		log.Printf("error scoring tags for version %d: %v", version.ID, err)
+	} else {
+		score = result.Score
+	}
 
-		// Hitung umur dari last update
-		ageInDays := time.Since(currentTag.UpdatedAt).Hours() / 24
-		newTrendingScore := float64(newCount) * math.Exp(-ageInDays/decayFactor)
+	version.ArticleTagRelationshipScore = score
+	return s.articleRepo.UpdateVersion(version.ID, map[string]interface{}{
+		"article_tag_relationship_score": score,
+	})
+}
 
-		// Cek perubahan
-		if newCount != currentTag.UsageCount || !floatAlmostEqual(newTrendingScore, currentTag.TrendingScore) {
-			currentTag.UsageCount = newCount
-			currentTag.TrendingScore = newTrendingScore
+// rescoreBatchSize - jumlah versi yang dibaca per query saat rescore
+const rescoreBatchSize = 500
+
+// RescoreArticleVersions menghitung ulang article_tag_relationship_score semua
+// versi dengan scorer yang sedang dipakai, misalnya setelah RELATIONSHIP_SCORER
+// diganti. Setiap versi dinilai dari tag-nya sendiri terhadap statistik saat
+// ini. Mengembalikan jumlah versi yang diproses.
+func (s *articleService) RescoreArticleVersions() (int, error) {
+	rescored := 0
+	var afterID uint
+	for {
+		versions, err := s.articleVersionRepo.GetVersionTagNames(afterID, rescoreBatchSize)
+		if err != nil {
+			return rescored, err
+		}
+		if len(versions) == 0 {
+			return rescored, nil
+		}
 
-			// Reset updated_at kalau ada usage baru
-			if newCount > currentTag.UsageCount {
-				currentTag.UpdatedAt = time.Now()
+		for _, version := range versions {
+			result, err := s.tagRelationshipScore(version.Tags)
+			if err != nil {
+				return rescored, err
 			}
-
-			tagsToUpdate = append(tagsToUpdate, *currentTag)
+			if err := s.articleVersionRepo.UpdateRelationshipScore(version.VersionID, result.Score); err != nil {
+				return rescored, err
+			}
+			rescored++
+			afterID = version.VersionID
 		}
 	}
-
-	if len(tagsToUpdate) > 0 {
-		_ = s.tagRepo.BulkUpdate(tagsToUpdate)
-	}
 }
 
 // floatAlmostEqual membandingkan float agar tidak sensitif terhadap perbedaan kecil
@@ -0,0 +1,91 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"cisdi-test-cms/helper"
+	"cisdi-test-cms/models"
+	"cisdi-test-cms/repositories"
+
+	"gorm.io/gorm"
+)
+
+// defaultSlug dipakai jika judul tidak menghasilkan karakter slug sama sekali
+const defaultSlug = "article"
+
+// GetPublicArticleBySlug mencari artikel published berdasarkan slug. Jika slug
+// hanya ditemukan di riwayat, artikel tidak dikembalikan tetapi slug terbarunya,
+// sehingga handler bisa melakukan redirect permanen.
+func (s *articleService) GetPublicArticleBySlug(slug string) (*models.Article, string, error) {
+	article, err := s.articleRepo.GetBySlug(slug)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, "", err
+		}
+
+		history, err := s.articleRepo.GetSlugHistory(slug)
+		if err != nil {
+			return nil, "", errors.New("article not found")
+		}
+
+		article, err = s.articleRepo.GetByID(history.ArticleID)
+		if err != nil {
+			return nil, "", errors.New("article not found")
+		}
+		if !isPubliclyVisible(article) {
+			return nil, "", errors.New("article not found")
+		}
+		return nil, article.Slug, nil
+	}
+
+	if !isPubliclyVisible(article) {
+		return nil, "", errors.New("article not found")
+	}
+
+	s.renderPublishedContent(article)
+	return article, "", nil
+}
+
+func isPubliclyVisible(article *models.Article) bool {
+	return article.PublishedVersion != nil && article.PublishedVersion.Status == models.StatusPublished
+}
+
+// uniqueSlug membuat slug dari judul dan menambahkan suffix -2, -3, dst. jika
+// slug sudah dipakai artikel lain. Slug milik articleID sendiri dianggap bebas.
+func uniqueSlug(articleRepo repositories.ArticleRepository, title string, articleID uint) (string, error) {
+	base := helper.Slugify(title)
+	if base == "" {
+		base = defaultSlug
+	}
+
+	candidate := base
+	for n := 2; ; n++ {
+		taken, err := articleRepo.IsSlugTaken(candidate, articleID)
+		if err != nil {
+			return "", fmt.Errorf("failed to check slug: %w", err)
+		}
+		if !taken {
+			return candidate, nil
+		}
+		candidate = fmt.Sprintf("%s-%d", base, n)
+	}
+}
+
+// syncArticleSlug menyesuaikan slug artikel dengan judul yang dipublikasikan.
+// Slug lama masuk ke riwayat sehingga tetap bisa di-resolve.
+func syncArticleSlug(articleRepo repositories.ArticleRepository, article *models.Article, title string) error {
+	slug, err := uniqueSlug(articleRepo, title, article.ID)
+	if err != nil {
+		return err
+	}
+	if slug == article.Slug {
+		return nil
+	}
+
+	if err := articleRepo.ChangeSlug(article.ID, article.Slug, slug); err != nil {
+		return fmt.Errorf("failed to update article slug: %w", err)
+	}
+	article.Slug = slug
+	return nil
+}
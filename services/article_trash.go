@@ -0,0 +1,115 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"cisdi-test-cms/authz"
+	"cisdi-test-cms/models"
+	"cisdi-test-cms/repositories"
+
+	"gorm.io/gorm"
+)
+
+var errNotInTrash = errors.New("article not found in trash")
+
+func (s *articleService) GetTrashedArticles(page, limit int, user authz.User) ([]models.TrashedArticle, int64, error) {
+	if !authz.Can(user, authz.ActionManageTrash, nil) {
+		return nil, 0, authz.ErrUnauthorized
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+	return s.articleRepo.GetTrashed(page, limit)
+}
+
+// RestoreArticle mengembalikan artikel dan versinya dari trash. Slug tidak
+// pernah dilepas selama di trash sehingga URL lama tetap berlaku.
+func (s *articleService) RestoreArticle(id uint, user authz.User) (*models.Article, error) {
+	if !authz.Can(user, authz.ActionManageTrash, nil) {
+		return nil, authz.ErrUnauthorized
+	}
+
+	err := s.uow.Do(func(repos repositories.Repositories) error {
+		if err := repos.Articles.Restore(id); err != nil {
+			return err
+		}
+		restored, err := repos.Articles.LockForUpdate(id)
+		if err != nil {
+			return err
+		}
+		return repos.Articles.UpdateTagStats(0, restored.LatestVersionID)
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errNotInTrash
+		}
+		return nil, fmt.Errorf("failed to restore article: %w", err)
+	}
+
+	article, err := s.articleRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if article.PublishedVersionID != nil {
+		s.notifyPublicationChanged()
+	}
+	return article, nil
+}
+
+// PurgeArticle menghapus permanen artikel yang sudah ada di trash.
+func (s *articleService) PurgeArticle(id uint, user authz.User) error {
+	if !authz.Can(user, authz.ActionManageTrash, nil) {
+		return authz.ErrUnauthorized
+	}
+
+	return s.purgeArticle(id)
+}
+
+// PurgeTrashedBefore dipakai job retensi untuk menghapus permanen artikel
+// yang dihapus sebelum cutoff.
+func (s *articleService) PurgeTrashedBefore(cutoff time.Time) error {
+	ids, err := s.articleRepo.GetTrashedIDsBefore(cutoff.UTC())
+	if err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	var errs []error
+	purged := 0
+	for _, id := range ids {
+		if err := s.purgeArticle(id); err != nil {
+			// Bisa saja sudah di-restore atau di-purge oleh admin sejak query di atas
+			if !errors.Is(err, errNotInTrash) {
+				errs = append(errs, fmt.Errorf("failed to purge article %d: %w", id, err))
+			}
+			continue
+		}
+		purged++
+	}
+
+	if purged > 0 {
+		log.Printf("retention: purged %d trashed articles deleted before %s", purged, cutoff.Format(time.RFC3339))
+	}
+
+	return errors.Join(errs...)
+}
+
+func (s *articleService) purgeArticle(id uint) error {
+	if err := s.articleRepo.Purge(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errNotInTrash
+		}
+		return err
+	}
+	return nil
+}
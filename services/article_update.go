@@ -0,0 +1,147 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"cisdi-test-cms/authz"
+	"cisdi-test-cms/models"
+	"cisdi-test-cms/repositories"
+)
+
+// UpdateArticle mengubah metadata artikel (title, author, featured) dan mencatat
+// setiap field yang berubah di audit. Slug ikut mengikuti title baru, slug lama
+// masuk riwayat. If-Match bersifat opsional di sini karena konten artikel tidak
+// ikut berubah.
+func (s *articleService) UpdateArticle(id uint, req models.UpdateArticleRequest, user authz.User) (*models.Article, error) {
+	var title string
+	if req.Title != nil {
+		title = strings.TrimSpace(*req.Title)
+		if title == "" {
+			return nil, errors.New("title cannot be empty")
+		}
+	}
+
+	// URL sitemap artikel published ikut berubah bersama slug-nya
+	var publicationChanged bool
+
+	// Diulang jika slug baru bentrok dengan request lain
+	err := retryOnConflict(func() error {
+		publicationChanged = false
+		return s.uow.Do(func(repos repositories.Repositories) error {
+			article, err := repos.Articles.LockForUpdate(id)
+			if err != nil {
+				return err
+			}
+			if !authz.Can(user, authz.ActionEdit, article) {
+				return authz.ErrUnauthorized
+			}
+
+			if err := checkVersionPrecondition(repos.Articles, article, req.IfMatch, nil, false); err != nil {
+				return err
+			}
+
+			fields := map[string]interface{}{}
+			var audits []models.ArticleAudit
+
+			if req.Title != nil && title != article.Title {
+				fields["title"] = title
+				audits = append(audits, newArticleAudit(article.ID, user.ID, "title", article.Title, title, models.AuditSourcePatch))
+			}
+
+			if req.AuthorID != nil && *req.AuthorID != article.AuthorID {
+				if !authz.Can(user, authz.ActionTransfer, article) {
+					return authz.ErrUnauthorized
+				}
+				if _, err := repos.Users.GetByID(*req.AuthorID); err != nil {
+					return fmt.Errorf("author %d not found", *req.AuthorID)
+				}
+				fields["author_id"] = *req.AuthorID
+				audits = append(audits, newArticleAudit(article.ID, user.ID, "author_id",
+					strconv.FormatUint(uint64(article.AuthorID), 10), strconv.FormatUint(uint64(*req.AuthorID), 10), models.AuditSourcePatch))
+			}
+
+			if req.Featured != nil && *req.Featured != article.Featured {
+				if !authz.Can(user, authz.ActionFeature, article) {
+					return authz.ErrUnauthorized
+				}
+				fields["featured"] = *req.Featured
+				audits = append(audits, newArticleAudit(article.ID, user.ID, "featured",
+					strconv.FormatBool(article.Featured), strconv.FormatBool(*req.Featured), models.AuditSourcePatch))
+			}
+
+			if len(fields) == 0 {
+				return nil
+			}
+
+			if err := repos.Articles.UpdateFields(article.ID, fields); err != nil {
+				return fmt.Errorf("failed to update article: %w", err)
+			}
+			if _, ok := fields["title"]; ok {
+				oldSlug := article.Slug
+				if err := syncArticleSlug(repos.Articles, article, title); err != nil {
+					return err
+				}
+				publicationChanged = article.Slug != oldSlug && article.PublishedVersionID != nil
+			}
+			return repos.Articles.CreateAudits(audits)
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if publicationChanged {
+		s.notifyPublicationChanged()
+	}
+
+	return s.articleRepo.GetByID(id)
+}
+
+func (s *articleService) GetArticleAudits(articleID uint, user authz.User) ([]models.ArticleAudit, error) {
+	article, err := s.articleRepo.GetByID(articleID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !authz.Can(user, authz.ActionView, article) {
+		return nil, authz.ErrUnauthorized
+	}
+
+	return s.articleRepo.GetAudits(articleID)
+}
+
+// syncArticleTitle menyamakan judul artikel dengan judul versi yang
+// dipublikasikan. actorID 0 berarti perubahan dilakukan scheduler.
+func syncArticleTitle(articleRepo repositories.ArticleRepository, article *models.Article, title string, actorID uint) error {
+	if title == article.Title {
+		return nil
+	}
+
+	if err := articleRepo.UpdateFields(article.ID, map[string]interface{}{"title": title}); err != nil {
+		return fmt.Errorf("failed to update article title: %w", err)
+	}
+	audit := newArticleAudit(article.ID, actorID, "title", article.Title, title, models.AuditSourcePublish)
+	if err := articleRepo.CreateAudits([]models.ArticleAudit{audit}); err != nil {
+		return fmt.Errorf("failed to record article audit: %w", err)
+	}
+
+	article.Title = title
+	return nil
+}
+
+func newArticleAudit(articleID, actorID uint, field, oldValue, newValue, source string) models.ArticleAudit {
+	audit := models.ArticleAudit{
+		ArticleID: articleID,
+		Field:     field,
+		OldValue:  oldValue,
+		NewValue:  newValue,
+		Source:    source,
+	}
+	if actorID != 0 {
+		audit.ActorID = &actorID
+	}
+	return audit
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"container/list"
+	"log"
+	"sync"
+
+	"cisdi-test-cms/helper"
+	"cisdi-test-cms/models"
+)
+
+// ContentRenderer merender konten versi artikel menjadi HTML yang aman ditampilkan.
+type ContentRenderer interface {
+	Render(version *models.ArticleVersion) string
+}
+
+type renderCacheEntry struct {
+	versionID uint
+	html      string
+}
+
+// contentRenderer menyimpan hasil render di LRU cache dengan key version ID.
+// Konten sebuah versi tidak pernah diubah setelah dibuat (perubahan selalu
+// menjadi versi baru), sehingga cache tidak perlu di-invalidate.
+type contentRenderer struct {
+	mu       sync.Mutex
+	capacity int
+	entries  map[uint]*list.Element
+	order    *list.List
+}
+
+func NewContentRenderer(cacheSize int) ContentRenderer {
+	return &contentRenderer{
+		capacity: cacheSize,
+		entries:  make(map[uint]*list.Element),
+		order:    list.New(),
+	}
+}
+
+func (r *contentRenderer) Render(version *models.ArticleVersion) string {
+	if version.ID == 0 {
+		return renderContent(version.ContentFormat, version.Content)
+	}
+
+	r.mu.Lock()
+	if el, ok := r.entries[version.ID]; ok {
+		r.order.MoveToFront(el)
+		html := el.Value.(*renderCacheEntry).html
+		r.mu.Unlock()
+		return html
+	}
+	r.mu.Unlock()
+
+	html := renderContent(version.ContentFormat, version.Content)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.entries[version.ID]; !ok && r.capacity > 0 {
+		r.entries[version.ID] = r.order.PushFront(&renderCacheEntry{versionID: version.ID, html: html})
+		if r.order.Len() > r.capacity {
+			oldest := r.order.Back()
+			r.order.Remove(oldest)
+			delete(r.entries, oldest.Value.(*renderCacheEntry).versionID)
+		}
+	}
+
+	return html
+}
+
+func renderContent(format models.ContentFormat, content string) string {
+	switch format {
+	case models.ContentFormatMarkdown:
+		html, err := helper.RenderMarkdown(content)
+		if err != nil {
+			log.Printf("failed to render markdown, falling back to plain text: %v", err)
+			return helper.RenderPlainText(content)
+		}
+		return html
+	case models.ContentFormatPlain:
+		return helper.RenderPlainText(content)
+	default:
+		return helper.SanitizeHTML(content)
+	}
+}
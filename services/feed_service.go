@@ -0,0 +1,116 @@
+package services
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"cisdi-test-cms/models"
+	"cisdi-test-cms/repositories"
+)
+
+// feedSummaryLength panjang maksimal ringkasan item feed (dalam rune)
+const feedSummaryLength = 300
+
+var htmlTagPattern = regexp.MustCompile(`<[^>]*>`)
+
+type FeedService interface {
+	GetFeed(params models.FeedParams) (*models.Feed, error)
+}
+
+type feedService struct {
+	articleRepo repositories.ArticleRepository
+	renderer    ContentRenderer
+	maxItems    int
+}
+
+func NewFeedService(articleRepo repositories.ArticleRepository, renderer ContentRenderer, maxItems int) FeedService {
+	return &feedService{
+		articleRepo: articleRepo,
+		renderer:    renderer,
+		maxItems:    maxItems,
+	}
+}
+
+// GetFeed mengambil artikel published terbaru (urut published_at) untuk feed.
+// Limit dari request dibatasi maksimal maxItems.
+func (s *feedService) GetFeed(params models.FeedParams) (*models.Feed, error) {
+	limit := params.Limit
+	if limit <= 0 || limit > s.maxItems {
+		limit = s.maxItems
+	}
+
+	articles, _, err := s.articleRepo.GetList(models.ArticleListParams{
+		AuthorID:  params.AuthorID,
+		TagID:     params.TagID,
+		Page:      1,
+		Limit:     limit,
+		SortBy:    "published_at",
+		SortOrder: "desc",
+	}, true)
+	if err != nil {
+		return nil, err
+	}
+
+	feed := &models.Feed{Items: []models.FeedItem{}}
+	hash := sha1.New()
+	fmt.Fprintf(hash, "tag=%d;author=%d;limit=%d\n", params.TagID, params.AuthorID, limit)
+
+	for _, article := range articles {
+		version := article.PublishedVersion
+		if version == nil || version.PublishedAt == nil {
+			continue
+		}
+
+		updatedAt := version.UpdatedAt
+		if article.UpdatedAt.After(updatedAt) {
+			updatedAt = article.UpdatedAt
+		}
+		if updatedAt.Before(*version.PublishedAt) {
+			updatedAt = *version.PublishedAt
+		}
+
+		tags := make([]string, 0, len(version.Tags))
+		for _, tag := range version.Tags {
+			tags = append(tags, tag.Name)
+		}
+
+		content := s.renderer.Render(version)
+		feed.Items = append(feed.Items, models.FeedItem{
+			ArticleID:   article.ID,
+			Slug:        article.Slug,
+			Title:       version.Title,
+			Summary:     summarize(content, feedSummaryLength),
+			Content:     content,
+			AuthorName:  article.Author.Username,
+			Tags:        tags,
+			PublishedAt: *version.PublishedAt,
+			UpdatedAt:   updatedAt,
+		})
+
+		if updatedAt.After(feed.Updated) {
+			feed.Updated = updatedAt
+		}
+		fmt.Fprintf(hash, "%d:%d:%s:%d\n", article.ID, version.ID, article.Slug, updatedAt.UnixNano())
+	}
+
+	feed.Version = hex.EncodeToString(hash.Sum(nil))
+	return feed, nil
+}
+
+// summarize membuang tag HTML dan memotong teks di batas kata.
+func summarize(content string, max int) string {
+	text := strings.Join(strings.Fields(htmlTagPattern.ReplaceAllString(content, " ")), " ")
+	if utf8.RuneCountInString(text) <= max {
+		return text
+	}
+
+	cut := string([]rune(text)[:max])
+	if i := strings.LastIndexByte(cut, ' '); i > 0 {
+		cut = cut[:i]
+	}
+	return cut + "…"
+}
@@ -0,0 +1,168 @@
+package services
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"time"
+
+	"cisdi-test-cms/models"
+	"cisdi-test-cms/repositories"
+)
+
+type RelatedArticleService interface {
+	GetRelatedArticles(articleID uint, limit int) ([]models.RelatedArticle, error)
+}
+
+type relatedArticleService struct {
+	articleRepo repositories.ArticleRepository
+	renderer    ContentRenderer
+	maxItems    int
+	halfLife    time.Duration
+}
+
+func NewRelatedArticleService(articleRepo repositories.ArticleRepository, renderer ContentRenderer, maxItems int, halfLife time.Duration) RelatedArticleService {
+	return &relatedArticleService{
+		articleRepo: articleRepo,
+		renderer:    renderer,
+		maxItems:    maxItems,
+		halfLife:    halfLife,
+	}
+}
+
+// GetRelatedArticles mengurutkan artikel published lain berdasarkan tag yang
+// berbobot, memakai statistik PMI yang sama dengan CalculateTagRelationshipScore:
+//   - tag yang sama berbobot PMI(t, t) = -ln p(t), sehingga tag langka lebih berarti
+//   - tag lain berbobot PMI positif tertinggi terhadap salah satu tag artikel
+//
+// Skor artikel adalah jumlah bobot tag versi published-nya, dikali decay
+// 0.5^(umur/halfLife) jika halfLife diset. Limit dibatasi maksimal maxItems.
+func (s *relatedArticleService) GetRelatedArticles(articleID uint, limit int) ([]models.RelatedArticle, error) {
+	if limit <= 0 || limit > s.maxItems {
+		limit = s.maxItems
+	}
+
+	article, err := s.articleRepo.GetByID(articleID)
+	if err != nil {
+		return nil, err
+	}
+	if !isPubliclyVisible(article) {
+		return nil, errors.New("article not found")
+	}
+
+	sourceTags := make([]string, 0, len(article.PublishedVersion.Tags))
+	for _, tag := range article.PublishedVersion.Tags {
+		sourceTags = append(sourceTags, tag.Name)
+	}
+	if len(sourceTags) == 0 {
+		return []models.RelatedArticle{}, nil
+	}
+
+	weights, err := s.tagWeights(sourceTags)
+	if err != nil {
+		return nil, err
+	}
+	weightedTags := make([]string, 0, len(weights))
+	for name := range weights {
+		weightedTags = append(weightedTags, name)
+	}
+
+	matches, err := s.articleRepo.GetPublishedTagMatches(weightedTags, article.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	candidates := make(map[uint]*models.RelatedArticle)
+	publishedAt := make(map[uint]time.Time)
+	for _, match := range matches {
+		candidate, ok := candidates[match.ArticleID]
+		if !ok {
+			candidate = &models.RelatedArticle{MatchedTags: []string{}}
+			candidates[match.ArticleID] = candidate
+			if match.PublishedAt != nil {
+				publishedAt[match.ArticleID] = *match.PublishedAt
+			}
+		}
+		candidate.Score += weights[match.TagName]
+		candidate.MatchedTags = append(candidate.MatchedTags, match.TagName)
+	}
+
+	ids := make([]uint, 0, len(candidates))
+	for id, candidate := range candidates {
+		if s.halfLife > 0 {
+			if at, ok := publishedAt[id]; ok && now.After(at) {
+				candidate.Score *= math.Pow(0.5, float64(now.Sub(at))/float64(s.halfLife))
+			}
+		}
+		if candidate.Score > 0 {
+			ids = append(ids, id)
+		}
+	}
+
+	sort.Slice(ids, func(i, j int) bool {
+		a, b := candidates[ids[i]], candidates[ids[j]]
+		if !floatAlmostEqual(a.Score, b.Score) {
+			return a.Score > b.Score
+		}
+		return publishedAt[ids[i]].After(publishedAt[ids[j]])
+	})
+	if len(ids) > limit {
+		ids = ids[:limit]
+	}
+
+	related := make([]models.RelatedArticle, 0, len(ids))
+	for _, id := range ids {
+		relatedArticle, err := s.articleRepo.GetByID(id)
+		if err != nil {
+			return nil, err
+		}
+		if relatedArticle.PublishedVersion != nil {
+			relatedArticle.PublishedVersion.ContentHTML = s.renderer.Render(relatedArticle.PublishedVersion)
+		}
+
+		candidate := candidates[id]
+		candidate.Article = *relatedArticle
+		sort.Strings(candidate.MatchedTags)
+		related = append(related, *candidate)
+	}
+	return related, nil
+}
+
+// tagWeights menghitung bobot tiap tag yang relevan dengan sourceTags: tag
+// sumber berbobot -ln p(t), tag lain berbobot PMI positif tertingginya.
+func (s *relatedArticleService) tagWeights(sourceTags []string) (map[string]float64, error) {
+	totalArticles, err := s.articleRepo.GetTotalArticleCount()
+	if err != nil {
+		return nil, err
+	}
+	coOccurring, err := s.articleRepo.GetCoOccurringTags(sourceTags)
+	if err != nil {
+		return nil, err
+	}
+
+	names := append([]string{}, sourceTags...)
+	for name := range coOccurring {
+		names = append(names, name)
+	}
+	freq, err := s.articleRepo.GetTagStatFrequencies(names)
+	if err != nil {
+		return nil, err
+	}
+
+	weights := make(map[string]float64)
+	for _, name := range sourceTags {
+		if pmi, ok := pointwiseMutualInformation(freq[name], freq[name], freq[name], totalArticles); ok && pmi > 0 {
+			weights[name] = pmi
+		}
+	}
+	for name, seeds := range coOccurring {
+		for seed, coOccur := range seeds {
+			pmi, ok := pointwiseMutualInformation(coOccur, freq[seed], freq[name], totalArticles)
+			if ok && pmi > weights[name] {
+				weights[name] = pmi
+			}
+		}
+	}
+	return weights, nil
+}
@@ -0,0 +1,151 @@
+package services
+
+import (
+	"cisdi-test-cms/models"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Nama algoritma yang bisa dipilih lewat RELATIONSHIP_SCORER
+const (
+	RelationshipScorerPMI     = "pmi"
+	RelationshipScorerPPMI    = "ppmi"
+	RelationshipScorerNPMI    = "npmi"
+	RelationshipScorerJaccard = "jaccard"
+)
+
+// RelationshipScorer menghitung skor hubungan satu pasangan tag dari jumlah
+// artikel yang memakai masing-masing tag, jumlah artikel yang memakai keduanya
+// dan total artikel. ok bernilai false jika pasangan tidak bisa dinilai
+// sehingga tidak ikut dirata-rata: salah satu frekuensi nol, atau untuk varian
+// PMI pasangan yang tidak pernah muncul bersama (log 0 tidak terdefinisi).
+// Jaccard tetap menilai pasangan seperti itu dengan 0.
+type RelationshipScorer interface {
+	Name() string
+	PairScore(coOccur, freqA, freqB int, total int64) (score float64, ok bool)
+}
+
+// NewRelationshipScorer memilih implementasi berdasarkan nama (tidak
+// membedakan huruf besar/kecil)
+func NewRelationshipScorer(name string) (RelationshipScorer, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case RelationshipScorerPMI:
+		return pmiScorer{}, nil
+	case RelationshipScorerPPMI:
+		return ppmiScorer{}, nil
+	case RelationshipScorerNPMI:
+		return npmiScorer{}, nil
+	case RelationshipScorerJaccard:
+		return jaccardScorer{}, nil
+	}
+	return nil, fmt.Errorf("unknown relationship scorer %q", name)
+}
+
+// pmiScorer - PMI mentah, log(p(a,b) / (p(a) p(b))). Tidak terbatas dan
+// negatif untuk pasangan yang jarang muncul bersama.
+type pmiScorer struct{}
+
+func (pmiScorer) Name() string { return RelationshipScorerPMI }
+
+func (pmiScorer) PairScore(coOccur, freqA, freqB int, total int64) (float64, bool) {
+	return pointwiseMutualInformation(coOccur, freqA, freqB, total)
+}
+
+// ppmiScorer - Positive PMI, nilai negatif dipotong ke 0
+type ppmiScorer struct{}
+
+func (ppmiScorer) Name() string { return RelationshipScorerPPMI }
+
+func (ppmiScorer) PairScore(coOccur, freqA, freqB int, total int64) (float64, bool) {
+	pmi, ok := pointwiseMutualInformation(coOccur, freqA, freqB, total)
+	if !ok {
+		return 0, false
+	}
+	return math.Max(pmi, 0), true
+}
+
+// npmiScorer - PMI dinormalisasi dengan -log p(a,b) sehingga berada di [-1, 1];
+// 1 berarti kedua tag selalu muncul bersama.
+type npmiScorer struct{}
+
+func (npmiScorer) Name() string { return RelationshipScorerNPMI }
+
+func (npmiScorer) PairScore(coOccur, freqA, freqB int, total int64) (float64, bool) {
+	pmi, ok := pointwiseMutualInformation(coOccur, freqA, freqB, total)
+	if !ok {
+		return 0, false
+	}
+	// p(a,b) = 1: semua artikel memakai kedua tag
+	selfInfo := -math.Log(float64(coOccur) / float64(total))
+	if selfInfo <= 0 {
+		return 1, true
+	}
+	return pmi / selfInfo, true
+}
+
+// jaccardScorer - |A ∩ B| / |A ∪ B| di [0, 1], tidak bergantung pada total
+// artikel. Pasangan yang tidak pernah muncul bersama bernilai 0.
+type jaccardScorer struct{}
+
+func (jaccardScorer) Name() string { return RelationshipScorerJaccard }
+
+func (jaccardScorer) PairScore(coOccur, freqA, freqB int, total int64) (float64, bool) {
+	if freqA == 0 || freqB == 0 {
+		return 0, false
+	}
+	union := freqA + freqB - coOccur
+	if union <= 0 {
+		return 0, false
+	}
+	return float64(coOccur) / float64(union), true
+}
+
+// scoreTagPairs menilai semua pasangan tags memakai statistik dari
+// GetTagStatFrequencies dan GetTagStatPairCoOccurrences, dan mengembalikan
+// rata-rata skor pasangan yang bisa dinilai (0 jika tidak ada) beserta rincian
+// per pasangan.
+func scoreTagPairs(scorer RelationshipScorer, tags []string, freq, coOccur map[string]int, total int64) (float64, []models.TagPairScore) {
+	pairs := make([]models.TagPairScore, 0, len(tags)*(len(tags)-1)/2)
+	scoreSum := 0.0
+	pairCount := 0
+	for i := 0; i < len(tags)-1; i++ {
+		for j := i + 1; j < len(tags); j++ {
+			a, b := tags[i], tags[j]
+			if a > b {
+				a, b = b, a
+			}
+			pair := models.TagPairScore{
+				TagA:          a,
+				TagB:          b,
+				FrequencyA:    freq[a],
+				FrequencyB:    freq[b],
+				CoOccurrences: coOccur[a+"|"+b],
+			}
+			pair.Score, pair.Counted = scorer.PairScore(pair.CoOccurrences, pair.FrequencyA, pair.FrequencyB, total)
+			if pair.Counted {
+				scoreSum += pair.Score
+				pairCount++
+			}
+			pairs = append(pairs, pair)
+		}
+	}
+
+	if pairCount == 0 {
+		return 0, pairs
+	}
+	return scoreSum / float64(pairCount), pairs
+}
+
+// pointwiseMutualInformation menghitung PMI satu pasangan tag; ok bernilai
+// false jika salah satu frekuensi nol.
+func pointwiseMutualInformation(coOccur, freqA, freqB int, total int64) (float64, bool) {
+	if coOccur == 0 || freqA == 0 || freqB == 0 || total == 0 {
+		return 0, false
+	}
+	totalF := float64(total)
+	pA := float64(freqA) / totalF
+	pB := float64(freqB) / totalF
+	pBoth := float64(coOccur) / totalF
+	return math.Log(pBoth / (pA * pB)), true
+}
@@ -0,0 +1,31 @@
+package services
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"cisdi-test-cms/repositories"
+)
+
+// conflictRetryAttempts jumlah percobaan maksimal untuk transaksi yang gagal
+// karena unique constraint dilanggar oleh request lain yang berjalan bersamaan
+const conflictRetryAttempts = 5
+
+// retryOnConflict menjalankan ulang fn (biasanya satu unit of work penuh)
+// selama gagal karena unique violation, dengan backoff kecil yang diacak.
+// Error lain langsung dikembalikan.
+func retryOnConflict(fn func() error) error {
+	var err error
+	for attempt := 1; attempt <= conflictRetryAttempts; attempt++ {
+		err = fn()
+		if err == nil || !repositories.IsUniqueViolation(err) {
+			return err
+		}
+		if attempt < conflictRetryAttempts {
+			backoff := time.Duration(attempt) * 10 * time.Millisecond
+			time.Sleep(backoff + time.Duration(rand.Int63n(int64(backoff))))
+		}
+	}
+	return fmt.Errorf("conflict persisted after %d attempts: %w", conflictRetryAttempts, err)
+}
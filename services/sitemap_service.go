@@ -0,0 +1,157 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"sync"
+	"time"
+
+	"cisdi-test-cms/models"
+	"cisdi-test-cms/repositories"
+)
+
+// SitemapMaxURLs batas jumlah URL per file sitemap sesuai protokol sitemaps.org
+const SitemapMaxURLs = 50000
+
+// PublicationListener dipanggil setiap kali daftar artikel yang terlihat publik berubah.
+type PublicationListener interface {
+	PublicationChanged()
+}
+
+type SitemapService interface {
+	PublicationListener
+	GetSitemap() (*models.Sitemap, error)
+}
+
+// sitemapService menyimpan sitemap yang sudah dibangun di memory. Cache ditandai
+// stale oleh PublicationChanged lalu dibangun ulang di background, sehingga
+// request berikutnya tidak perlu menunggu query ke database. PublicationChanged
+// hanya terpanggil di proses yang melakukan perubahan, jadi cache juga
+// kedaluwarsa setelah ttl agar perubahan dari replica lain dan dari tag
+// (rename, merge, delete) tetap terbawa.
+type sitemapService struct {
+	articleRepo repositories.ArticleRepository
+	tagRepo     repositories.TagRepository
+	pageSize    int
+	ttl         time.Duration
+
+	mu      sync.Mutex
+	cache   *models.Sitemap
+	stale   bool
+	buildMu sync.Mutex
+}
+
+// NewSitemapService membuat SitemapService; ttl <= 0 berarti cache hanya
+// dibangun ulang lewat PublicationChanged.
+func NewSitemapService(articleRepo repositories.ArticleRepository, tagRepo repositories.TagRepository, pageSize int, ttl time.Duration) SitemapService {
+	if pageSize <= 0 || pageSize > SitemapMaxURLs {
+		pageSize = SitemapMaxURLs
+	}
+	return &sitemapService{
+		articleRepo: articleRepo,
+		tagRepo:     tagRepo,
+		pageSize:    pageSize,
+		ttl:         ttl,
+		stale:       true,
+	}
+}
+
+func (s *sitemapService) PublicationChanged() {
+	s.mu.Lock()
+	s.stale = true
+	s.mu.Unlock()
+
+	go func() {
+		if _, err := s.GetSitemap(); err != nil {
+			log.Printf("failed to regenerate sitemap: %v", err)
+		}
+	}()
+}
+
+func (s *sitemapService) GetSitemap() (*models.Sitemap, error) {
+	// Hanya satu build yang berjalan, request lain menunggu hasilnya
+	s.buildMu.Lock()
+	defer s.buildMu.Unlock()
+
+	s.mu.Lock()
+	expired := s.cache != nil && s.ttl > 0 && time.Since(s.cache.GeneratedAt) > s.ttl
+	if !s.stale && !expired && s.cache != nil {
+		cached := s.cache
+		s.mu.Unlock()
+		return cached, nil
+	}
+	// Perubahan yang terjadi selama build akan menandai stale lagi
+	s.stale = false
+	s.mu.Unlock()
+
+	sitemap, err := s.build()
+	if err != nil {
+		s.mu.Lock()
+		s.stale = true
+		s.mu.Unlock()
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.cache = sitemap
+	s.mu.Unlock()
+
+	return sitemap, nil
+}
+
+func (s *sitemapService) build() (*models.Sitemap, error) {
+	articles, err := s.articleRepo.GetPublishedForSitemap()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get published articles: %w", err)
+	}
+
+	tags, err := s.tagRepo.GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tags: %w", err)
+	}
+
+	// usage_count di tabel tags baru diperbarui job trending, sitemap butuh data terkini
+	usage, err := s.articleRepo.GetTagUsage()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tag usage: %w", err)
+	}
+
+	entries := make([]models.SitemapEntry, 0, len(articles)+len(tags))
+	for _, article := range articles {
+		entries = append(entries, models.SitemapEntry{
+			Path:    "/api/v1/public/articles/by-slug/" + url.PathEscape(article.Slug),
+			LastMod: article.LastMod,
+		})
+	}
+
+	// Landing page tag hanya untuk tag yang dipakai artikel published
+	for _, tag := range tags {
+		tagUsage := usage[tag.ID]
+		if tagUsage.Count == 0 {
+			continue
+		}
+		lastMod := tag.UpdatedAt
+		if tagUsage.LastUsedAt != nil {
+			lastMod = *tagUsage.LastUsedAt
+		}
+		entries = append(entries, models.SitemapEntry{
+			Path:    fmt.Sprintf("/api/v1/public/articles?tag_id=%d", tag.ID),
+			LastMod: lastMod,
+		})
+	}
+
+	sitemap := &models.Sitemap{
+		Pages:       [][]models.SitemapEntry{},
+		GeneratedAt: time.Now(),
+	}
+	for start := 0; start < len(entries); start += s.pageSize {
+		end := start + s.pageSize
+		if end > len(entries) {
+			end = len(entries)
+		}
+		sitemap.Pages = append(sitemap.Pages, entries[start:end])
+	}
+
+	return sitemap, nil
+}
@@ -0,0 +1,58 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+var ErrEmptyTagName = errors.New("tag name cannot be empty")
+
+// TagNormalizer menyeragamkan nama tag sebelum dicari atau disimpan, sehingga
+// "Go", "go " dan "GO" menjadi satu tag. Urutannya: unicode NFC, trim dan
+// penyederhanaan whitespace, case folding, lalu validasi panjang dan karakter.
+type TagNormalizer struct {
+	maxLength  int
+	caseFold   bool
+	disallowed string
+}
+
+func NewTagNormalizer(maxLength int, caseFold bool, disallowed string) *TagNormalizer {
+	return &TagNormalizer{
+		maxLength:  maxLength,
+		caseFold:   caseFold,
+		disallowed: disallowed,
+	}
+}
+
+// Normalize mengembalikan bentuk kanonik nama tag, atau error jika nama tidak valid.
+func (n *TagNormalizer) Normalize(name string) (string, error) {
+	name = norm.NFC.String(name)
+
+	// strings.Fields sekaligus membuang whitespace di awal/akhir
+	name = strings.Join(strings.Fields(name), " ")
+
+	if n.caseFold {
+		// Fold (bukan ToLower) agar mis. "STRASSE" dan "straße" dianggap sama
+		name = norm.NFC.String(cases.Fold().String(name))
+	}
+
+	if name == "" {
+		return "", ErrEmptyTagName
+	}
+	if n.maxLength > 0 && utf8.RuneCountInString(name) > n.maxLength {
+		return "", fmt.Errorf("tag name %q is longer than %d characters", name, n.maxLength)
+	}
+	for _, r := range name {
+		if unicode.IsControl(r) || strings.ContainsRune(n.disallowed, r) {
+			return "", fmt.Errorf("tag name %q contains disallowed character %q", name, r)
+		}
+	}
+
+	return name, nil
+}
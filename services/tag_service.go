@@ -5,6 +5,9 @@ import (
 	"cisdi-test-cms/models"
 	"cisdi-test-cms/repositories"
 	"errors"
+	"fmt"
+	"log"
+	"sort"
 
 	"gorm.io/gorm"
 )
@@ -13,23 +16,41 @@ type TagService interface {
 	CreateTag(req models.CreateTagRequest) (*models.Tag, error)
 	GetTags() ([]models.Tag, error)
 	GetTag(id uint) (*models.Tag, error)
+	RenameTag(id uint, req models.RenameTagRequest) (*models.Tag, error)
+	MergeTag(sourceID uint, req models.MergeTagRequest) (*models.Tag, error)
+	DeleteTag(id uint) error
+	AddTagAlias(tagID uint, req models.CreateTagAliasRequest) (*models.Tag, error)
+	RemoveTagAlias(tagID, aliasID uint) error
+	MergeDuplicateTags(dryRun bool) ([]models.TagDuplicateGroup, error)
+	SetTagParent(id uint, req models.SetTagParentRequest) (*models.Tag, error)
+	GetTagTree() ([]models.TagTreeNode, error)
+	SuggestTags(req models.SuggestTagsRequest) ([]models.TagSuggestion, error)
 }
 
 type tagService struct {
 	tagRepo     repositories.TagRepository
 	articleRepo repositories.ArticleRepository
+	normalizer  *TagNormalizer
+	scorer      RelationshipScorer
 }
 
-func NewTagService(tagRepo repositories.TagRepository, articleRepo repositories.ArticleRepository) TagService {
+func NewTagService(tagRepo repositories.TagRepository, articleRepo repositories.ArticleRepository, normalizer *TagNormalizer, scorer RelationshipScorer) TagService {
 	return &tagService{
 		tagRepo:     tagRepo,
 		articleRepo: articleRepo,
+		normalizer:  normalizer,
+		scorer:      scorer,
 	}
 }
 
 func (s *tagService) CreateTag(req models.CreateTagRequest) (*models.Tag, error) {
+	name, err := s.normalizer.Normalize(req.Name)
+	if err != nil {
+		return nil, err
+	}
+
 	// Check if tag already exists
-	_, err := s.tagRepo.GetByName(req.Name)
+	_, err = s.tagRepo.GetByName(name)
 	if err == nil {
 		return nil, errors.New("tag already exists")
 	}
@@ -37,9 +58,32 @@ func (s *tagService) CreateTag(req models.CreateTagRequest) (*models.Tag, error)
 		return nil, err
 	}
 
+	if err := s.checkAliasFree(name, 0); err != nil {
+		return nil, err
+	}
+
+	if req.ParentID != nil {
+		if _, err := s.tagRepo.GetByID(*req.ParentID); err != nil {
+			return nil, fmt.Errorf("parent tag not found: %w", err)
+		}
+	}
+
+	// Tag yang pernah dihapus dipulihkan, namanya masih terkena unique index
+	deleted, err := s.tagRepo.GetByNameUnscoped(name)
+	if err == nil {
+		if err := s.tagRepo.Restore(deleted.ID); err != nil {
+			return nil, err
+		}
+		if err := s.tagRepo.SetParent(deleted.ID, req.ParentID); err != nil {
+			return nil, err
+		}
+		return s.tagRepo.GetByID(deleted.ID)
+	}
+
 	// Create new tag
 	tag := &models.Tag{
-		Name:          req.Name,
+		Name:          name,
+		ParentID:      req.ParentID,
 		UsageCount:    0,
 		TrendingScore: 0,
 	}
@@ -58,3 +102,279 @@ func (s *tagService) GetTags() ([]models.Tag, error) {
 func (s *tagService) GetTag(id uint) (*models.Tag, error) {
 	return s.tagRepo.GetByID(id)
 }
+
+// RenameTag mengganti nama tag; nama lama otomatis menjadi alias. Jika nama
+// baru sudah dipakai tag lain, gunakan MergeTag.
+func (s *tagService) RenameTag(id uint, req models.RenameTagRequest) (*models.Tag, error) {
+	tag, err := s.tagRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := s.normalizer.Normalize(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	if name == tag.Name {
+		return tag, nil
+	}
+
+	if err := s.checkNameFree(name); err != nil {
+		return nil, err
+	}
+	if err := s.checkAliasFree(name, tag.ID); err != nil {
+		return nil, err
+	}
+
+	if err := s.tagRepo.Rename(tag.ID, tag.Name, name); err != nil {
+		return nil, fmt.Errorf("failed to rename tag: %w", err)
+	}
+	return s.tagRepo.GetByID(tag.ID)
+}
+
+// MergeTag menggabungkan tag sourceID ke req.TargetID: semua versi yang memakai
+// tag sumber dipindah ke tag tujuan, dan nama tag sumber menjadi alias.
+func (s *tagService) MergeTag(sourceID uint, req models.MergeTagRequest) (*models.Tag, error) {
+	if sourceID == req.TargetID {
+		return nil, errors.New("cannot merge a tag into itself")
+	}
+
+	if _, err := s.tagRepo.GetByID(sourceID); err != nil {
+		return nil, fmt.Errorf("source tag not found: %w", err)
+	}
+	if _, err := s.tagRepo.GetByID(req.TargetID); err != nil {
+		return nil, fmt.Errorf("target tag not found: %w", err)
+	}
+
+	if err := s.tagRepo.Merge(sourceID, req.TargetID); err != nil {
+		return nil, fmt.Errorf("failed to merge tags: %w", err)
+	}
+
+	// Baris article_version_tags berpindah tag, statistik tag dihitung ulang
+	if err := s.articleRepo.RebuildTagStats(); err != nil {
+		return nil, fmt.Errorf("failed to rebuild tag stats: %w", err)
+	}
+	return s.tagRepo.GetByID(req.TargetID)
+}
+
+func (s *tagService) DeleteTag(id uint) error {
+	if _, err := s.tagRepo.GetByID(id); err != nil {
+		return err
+	}
+
+	if err := s.tagRepo.Delete(id); err != nil {
+		return fmt.Errorf("failed to delete tag: %w", err)
+	}
+	return nil
+}
+
+func (s *tagService) AddTagAlias(tagID uint, req models.CreateTagAliasRequest) (*models.Tag, error) {
+	tag, err := s.tagRepo.GetByID(tagID)
+	if err != nil {
+		return nil, err
+	}
+
+	alias, err := s.normalizer.Normalize(req.Alias)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkNameFree(alias); err != nil {
+		return nil, err
+	}
+	if err := s.checkAliasFree(alias, 0); err != nil {
+		return nil, err
+	}
+
+	if err := s.tagRepo.CreateAlias(&models.TagAlias{TagID: tag.ID, Alias: alias}); err != nil {
+		return nil, fmt.Errorf("failed to create alias: %w", err)
+	}
+	return s.tagRepo.GetByID(tag.ID)
+}
+
+func (s *tagService) RemoveTagAlias(tagID, aliasID uint) error {
+	if err := s.tagRepo.DeleteAlias(tagID, aliasID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("alias not found")
+		}
+		return err
+	}
+	return nil
+}
+
+// SetTagParent memindahkan tag di hierarki. Parent tidak boleh tag itu sendiri
+// atau turunannya.
+func (s *tagService) SetTagParent(id uint, req models.SetTagParentRequest) (*models.Tag, error) {
+	if _, err := s.tagRepo.GetByID(id); err != nil {
+		return nil, err
+	}
+	if req.ParentID != nil {
+		if _, err := s.tagRepo.GetByID(*req.ParentID); err != nil {
+			return nil, fmt.Errorf("parent tag not found: %w", err)
+		}
+	}
+
+	if err := s.tagRepo.SetParent(id, req.ParentID); err != nil {
+		return nil, err
+	}
+	return s.tagRepo.GetByID(id)
+}
+
+// GetTagTree menyusun semua tag menjadi pohon. Tag yang parent-nya sudah tidak
+// aktif ditampilkan sebagai root.
+func (s *tagService) GetTagTree() ([]models.TagTreeNode, error) {
+	tags, err := s.tagRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(tags, func(i, j int) bool { return tags[i].Name < tags[j].Name })
+
+	exists := make(map[uint]bool, len(tags))
+	for _, tag := range tags {
+		exists[tag.ID] = true
+	}
+
+	children := make(map[uint][]models.Tag)
+	var roots []models.Tag
+	for _, tag := range tags {
+		if tag.ParentID != nil && exists[*tag.ParentID] {
+			children[*tag.ParentID] = append(children[*tag.ParentID], tag)
+		} else {
+			roots = append(roots, tag)
+		}
+	}
+
+	var build func(tags []models.Tag) []models.TagTreeNode
+	build = func(tags []models.Tag) []models.TagTreeNode {
+		nodes := make([]models.TagTreeNode, 0, len(tags))
+		for _, tag := range tags {
+			nodes = append(nodes, models.TagTreeNode{Tag: tag, Children: build(children[tag.ID])})
+		}
+		return nodes
+	}
+	return build(roots), nil
+}
+
+// MergeDuplicateTags mencari tag yang namanya sama setelah normalisasi (mis.
+// "Go", "go " dan "GO"), menggabungkannya ke satu tag kanonik dan mengganti nama
+// tag kanonik ke bentuk ternormalisasi. Dengan dryRun tidak ada yang diubah.
+func (s *tagService) MergeDuplicateTags(dryRun bool) ([]models.TagDuplicateGroup, error) {
+	tags, err := s.tagRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string][]models.Tag)
+	for _, tag := range tags {
+		name, err := s.normalizer.Normalize(tag.Name)
+		if err != nil {
+			log.Printf("tags: skipping tag %d (%q): %v", tag.ID, tag.Name, err)
+			continue
+		}
+		byName[name] = append(byName[name], tag)
+	}
+
+	var groups []models.TagDuplicateGroup
+	for name, candidates := range byName {
+		canonical := pickCanonicalTag(name, candidates)
+		if len(candidates) == 1 && canonical.Name == name {
+			continue
+		}
+
+		group := models.TagDuplicateGroup{Name: name, Canonical: canonical, Duplicates: []models.Tag{}}
+		for _, tag := range candidates {
+			if tag.ID != canonical.ID {
+				group.Duplicates = append(group.Duplicates, tag)
+			}
+		}
+		groups = append(groups, group)
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Name < groups[j].Name })
+
+	if dryRun || len(groups) == 0 {
+		return groups, nil
+	}
+
+	var errs []error
+	for _, group := range groups {
+		if err := s.applyDuplicateGroup(group); err != nil {
+			errs = append(errs, fmt.Errorf("tag %q: %w", group.Name, err))
+		}
+	}
+
+	if err := s.articleRepo.RebuildTagStats(); err != nil {
+		errs = append(errs, fmt.Errorf("failed to rebuild tag stats: %w", err))
+	}
+	return groups, errors.Join(errs...)
+}
+
+func (s *tagService) applyDuplicateGroup(group models.TagDuplicateGroup) error {
+	for _, duplicate := range group.Duplicates {
+		if err := s.tagRepo.Merge(duplicate.ID, group.Canonical.ID); err != nil {
+			return fmt.Errorf("failed to merge tag %d: %w", duplicate.ID, err)
+		}
+	}
+
+	if group.Canonical.Name == group.Name {
+		return nil
+	}
+	if err := s.checkNameFree(group.Name); err != nil {
+		return err
+	}
+	if err := s.checkAliasFree(group.Name, group.Canonical.ID); err != nil {
+		return err
+	}
+	return s.tagRepo.Rename(group.Canonical.ID, group.Canonical.Name, group.Name)
+}
+
+// pickCanonicalTag memilih tag yang dipertahankan: yang namanya sudah
+// ternormalisasi, lalu yang paling banyak dipakai, lalu yang paling lama.
+func pickCanonicalTag(name string, candidates []models.Tag) models.Tag {
+	best := candidates[0]
+	for _, tag := range candidates[1:] {
+		switch {
+		case (tag.Name == name) != (best.Name == name):
+			if tag.Name == name {
+				best = tag
+			}
+		case tag.UsageCount != best.UsageCount:
+			if tag.UsageCount > best.UsageCount {
+				best = tag
+			}
+		case tag.ID < best.ID:
+			best = tag
+		}
+	}
+	return best
+}
+
+// checkNameFree memastikan belum ada tag (termasuk yang di-soft delete) dengan
+// nama tersebut. Tag yang sudah ada harus digabung, bukan ditimpa.
+func (s *tagService) checkNameFree(name string) error {
+	existing, err := s.tagRepo.GetByNameUnscoped(name)
+	if err == nil {
+		if existing.DeletedAt.Valid {
+			return fmt.Errorf("tag %q was deleted, recreate it instead", name)
+		}
+		return fmt.Errorf("tag %q already exists (id %d), merge the tags instead", name, existing.ID)
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	return nil
+}
+
+// checkAliasFree memastikan nama belum menjadi alias tag lain. Alias milik
+// ownerID sendiri diperbolehkan (ownerID 0 berarti tidak ada pengecualian).
+func (s *tagService) checkAliasFree(name string, ownerID uint) error {
+	existing, err := s.tagRepo.GetAlias(name)
+	if err == nil {
+		if ownerID != 0 && existing.TagID == ownerID {
+			return nil
+		}
+		return fmt.Errorf("%q is already an alias of tag %d", name, existing.TagID)
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,212 @@
+package services
+
+import (
+	"cisdi-test-cms/models"
+	"errors"
+	"sort"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+	"gorm.io/gorm"
+)
+
+const defaultTagSuggestionLimit = 10
+
+// SuggestTags memberi saran tag untuk draft berdasarkan statistik yang sama
+// dengan CalculateTagRelationshipScore: kandidat diurutkan dari rata-rata skor
+// pasangan (RelationshipScorer yang dikonfigurasi) terhadap tag input, sehingga
+// menambahkannya cenderung menaikkan skor artikel.
+// Jika tidak ada tag input, tag yang namanya disebut di isi draft dipakai
+// sebagai input.
+func (s *tagService) SuggestTags(req models.SuggestTagsRequest) ([]models.TagSuggestion, error) {
+	if len(req.Tags) == 0 && strings.TrimSpace(req.Content) == "" {
+		return nil, errors.New("tags or content is required")
+	}
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultTagSuggestionLimit
+	}
+
+	seeds, err := s.lookupSuggestionSeeds(req.Tags)
+	if err != nil {
+		return nil, err
+	}
+
+	mentioned := make(map[string]models.Tag)
+	if strings.TrimSpace(req.Content) != "" {
+		mentioned, err = s.tagsMentionedIn(req.Content)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	seedNames := make([]string, 0, len(seeds))
+	if len(seeds) > 0 {
+		for name := range seeds {
+			seedNames = append(seedNames, name)
+		}
+	} else {
+		for name := range mentioned {
+			seedNames = append(seedNames, name)
+		}
+	}
+	isSeed := make(map[string]bool, len(seedNames))
+	for _, name := range seedNames {
+		isSeed[name] = true
+	}
+
+	coOccurring, err := s.articleRepo.GetCoOccurringTags(seedNames)
+	if err != nil {
+		return nil, err
+	}
+
+	candidateNames := make([]string, 0, len(coOccurring)+len(mentioned))
+	for name := range coOccurring {
+		candidateNames = append(candidateNames, name)
+	}
+	for name := range mentioned {
+		if !isSeed[name] {
+			if _, ok := coOccurring[name]; !ok {
+				candidateNames = append(candidateNames, name)
+			}
+		}
+	}
+	if len(candidateNames) == 0 {
+		return []models.TagSuggestion{}, nil
+	}
+
+	totalArticles, err := s.articleRepo.GetTotalArticleCount()
+	if err != nil {
+		return nil, err
+	}
+	freq, err := s.articleRepo.GetTagStatFrequencies(append(append([]string{}, seedNames...), candidateNames...))
+	if err != nil {
+		return nil, err
+	}
+	candidateTags, err := s.tagRepo.GetByNames(candidateNames)
+	if err != nil {
+		return nil, err
+	}
+
+	suggestions := make([]models.TagSuggestion, 0, len(candidateTags))
+	for _, tag := range candidateTags {
+		scoreSum := 0.0
+		pairCount := 0
+		coOccurTotal := 0
+		for seed, coOccur := range coOccurring[tag.Name] {
+			pairScore, ok := s.scorer.PairScore(coOccur, freq[seed], freq[tag.Name], totalArticles)
+			if !ok {
+				continue
+			}
+			scoreSum += pairScore
+			pairCount++
+			coOccurTotal += coOccur
+		}
+
+		_, isMentioned := mentioned[tag.Name]
+		score := 0.0
+		if pairCount > 0 {
+			score = scoreSum / float64(pairCount)
+		}
+		// Tag yang skornya tidak positif tidak membantu skor artikel
+		if score <= 0 && !isMentioned {
+			continue
+		}
+
+		suggestions = append(suggestions, models.TagSuggestion{
+			TagID:         tag.ID,
+			Name:          tag.Name,
+			Score:         score,
+			CoOccurrences: coOccurTotal,
+			Mentioned:     isMentioned,
+		})
+	}
+
+	sort.Slice(suggestions, func(i, j int) bool {
+		a, b := suggestions[i], suggestions[j]
+		if !floatAlmostEqual(a.Score, b.Score) {
+			return a.Score > b.Score
+		}
+		if a.Mentioned != b.Mentioned {
+			return a.Mentioned
+		}
+		if a.CoOccurrences != b.CoOccurrences {
+			return a.CoOccurrences > b.CoOccurrences
+		}
+		return a.Name < b.Name
+	})
+
+	if len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+	return suggestions, nil
+}
+
+// lookupSuggestionSeeds menormalisasi tag input dan mengarahkan alias ke tag
+// kanoniknya. Tag yang belum ada diabaikan karena belum punya statistik.
+func (s *tagService) lookupSuggestionSeeds(names []string) (map[string]models.Tag, error) {
+	seeds := make(map[string]models.Tag)
+	for _, raw := range names {
+		name, err := s.normalizer.Normalize(raw)
+		if errors.Is(err, ErrEmptyTagName) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		tag, err := s.tagRepo.GetByAlias(name)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			tag, err = s.tagRepo.GetByName(name)
+		}
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		seeds[tag.Name] = *tag
+	}
+	return seeds, nil
+}
+
+// tagsMentionedIn mencari tag yang namanya muncul utuh (per kata) di isi draft,
+// tanpa membedakan huruf besar/kecil.
+func (s *tagService) tagsMentionedIn(content string) (map[string]models.Tag, error) {
+	tags, err := s.tagRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	text := " " + strings.Join(suggestionTokens(htmlTagPattern.ReplaceAllString(content, " ")), " ") + " "
+	mentioned := make(map[string]models.Tag)
+	for _, tag := range tags {
+		tokens := suggestionTokens(tag.Name)
+		if len(tokens) == 0 {
+			continue
+		}
+		if strings.Contains(text, " "+strings.Join(tokens, " ")+" ") {
+			mentioned[tag.Name] = tag
+		}
+	}
+	return mentioned, nil
+}
+
+// suggestionTokens memecah teks per whitespace, membuang tanda baca di tepi
+// kata (tapi tidak di tengah, agar "node.js" tetap utuh) lalu case folding.
+func suggestionTokens(text string) []string {
+	fold := cases.Fold()
+	fields := strings.Fields(norm.NFC.String(text))
+	tokens := make([]string, 0, len(fields))
+	for _, field := range fields {
+		field = strings.TrimFunc(field, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '+' && r != '#'
+		})
+		if field != "" {
+			tokens = append(tokens, fold.String(field))
+		}
+	}
+	return tokens
+}
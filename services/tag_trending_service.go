@@ -0,0 +1,139 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"cisdi-test-cms/models"
+	"cisdi-test-cms/repositories"
+)
+
+// trendingWindowDays adalah panjang window trending dalam bucket harian (UTC).
+// Window berjalan selalu memuat bucket hari ini yang belum penuh, sedangkan
+// window sebelumnya terdiri dari hari penuh. "24h" bukan 24 jam bergulir
+// melainkan bucket hari ini dibandingkan dengan kemarin, sehingga asimetrinya
+// paling terasa: velocity-nya cenderung negatif di awal hari.
+var trendingWindowDays = map[string]int{
+	"24h": 1,
+	"7d":  7,
+	"30d": 30,
+}
+
+// trendingBucketRetentionDays cukup untuk window terpanjang beserta window sebelumnya
+const trendingBucketRetentionDays = 60
+
+type TagTrendingService interface {
+	RecomputeTagUsage(now time.Time) (*models.TagUsageRecompute, error)
+	GetTrendingTags(params models.TrendingTagParams, now time.Time) ([]models.TrendingTag, error)
+	PruneDailyUsage(now time.Time) error
+}
+
+type tagTrendingService struct {
+	articleRepo repositories.ArticleRepository
+	tagRepo     repositories.TagRepository
+	halfLife    time.Duration
+}
+
+func NewTagTrendingService(articleRepo repositories.ArticleRepository, tagRepo repositories.TagRepository, halfLife time.Duration) TagTrendingService {
+	return &tagTrendingService{
+		articleRepo: articleRepo,
+		tagRepo:     tagRepo,
+		halfLife:    halfLife,
+	}
+}
+
+// RecomputeTagUsage menghitung ulang usage_count (jumlah versi published yang
+// memakai tag) dan trending_score semua tag. Dijalankan berkala oleh job
+// recompute-tag-trending dan bisa dipicu admin.
+//
+// last_used_at adalah waktu publish terbaru yang memakai tag dan tidak pernah
+// mundur, meskipun artikelnya kemudian di-unpublish. trending_score adalah
+// usage_count * 0.5^(umur last_used_at / halfLife).
+func (s *tagTrendingService) RecomputeTagUsage(now time.Time) (*models.TagUsageRecompute, error) {
+	usage, err := s.articleRepo.GetTagUsage()
+	if err != nil {
+		return nil, fmt.Errorf("failed to count tag usage: %w", err)
+	}
+
+	allTags, err := s.tagRepo.GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load tags: %w", err)
+	}
+
+	var tagsToUpdate []models.Tag
+	for _, tag := range allTags {
+		tagUsage := usage[tag.ID]
+
+		lastUsedAt := tag.LastUsedAt
+		if tagUsage.LastUsedAt != nil && (lastUsedAt == nil || tagUsage.LastUsedAt.After(*lastUsedAt)) {
+			lastUsedAt = tagUsage.LastUsedAt
+		}
+
+		trendingScore := s.trendingScore(tagUsage.Count, lastUsedAt, now)
+
+		lastUsedChanged := (lastUsedAt == nil) != (tag.LastUsedAt == nil) ||
+			(lastUsedAt != nil && !lastUsedAt.Equal(*tag.LastUsedAt))
+		if tagUsage.Count == tag.UsageCount && floatAlmostEqual(trendingScore, tag.TrendingScore) && !lastUsedChanged {
+			continue
+		}
+
+		tag.UsageCount = tagUsage.Count
+		tag.TrendingScore = trendingScore
+		tag.LastUsedAt = lastUsedAt
+		tagsToUpdate = append(tagsToUpdate, tag)
+	}
+
+	if len(tagsToUpdate) > 0 {
+		if err := s.tagRepo.UpdateUsage(tagsToUpdate); err != nil {
+			return nil, fmt.Errorf("failed to update tag usage: %w", err)
+		}
+	}
+
+	return &models.TagUsageRecompute{
+		Tags:         len(allTags),
+		Updated:      len(tagsToUpdate),
+		RecomputedAt: now,
+	}, nil
+}
+
+func (s *tagTrendingService) trendingScore(count int, lastUsedAt *time.Time, now time.Time) float64 {
+	if count == 0 {
+		return 0
+	}
+	if lastUsedAt == nil || s.halfLife <= 0 || !now.After(*lastUsedAt) {
+		return float64(count)
+	}
+	return float64(count) * math.Pow(0.5, float64(now.Sub(*lastUsedAt))/float64(s.halfLife))
+}
+
+// GetTrendingTags mengurutkan tag berdasarkan jumlah publish di window
+// terakhir, beserta velocity terhadap window sebelumnya
+func (s *tagTrendingService) GetTrendingTags(params models.TrendingTagParams, now time.Time) ([]models.TrendingTag, error) {
+	days, ok := trendingWindowDays[params.Window]
+	if !ok {
+		return nil, fmt.Errorf("invalid window %q", params.Window)
+	}
+
+	today := now.UTC().Truncate(24 * time.Hour)
+	currentFrom := today.AddDate(0, 0, -(days - 1))
+	previousFrom := currentFrom.AddDate(0, 0, -days)
+
+	trending, err := s.tagRepo.GetTrending(params, currentFrom, previousFrom)
+	if err != nil {
+		return nil, err
+	}
+	if trending == nil {
+		trending = []models.TrendingTag{}
+	}
+	return trending, nil
+}
+
+// PruneDailyUsage menghapus bucket harian yang sudah tidak terjangkau window manapun
+func (s *tagTrendingService) PruneDailyUsage(now time.Time) error {
+	cutoff := now.UTC().Truncate(24*time.Hour).AddDate(0, 0, -trendingBucketRetentionDays)
+	if err := s.tagRepo.DeleteDailyUsageBefore(cutoff); err != nil {
+		return fmt.Errorf("failed to prune tag daily usage: %w", err)
+	}
+	return nil
+}
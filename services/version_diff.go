@@ -0,0 +1,302 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"cisdi-test-cms/authz"
+	"cisdi-test-cms/helper"
+	"cisdi-test-cms/models"
+)
+
+// diffContextLines jumlah baris konteks di sekitar perubahan pada setiap hunk
+const diffContextLines = 3
+
+// DiffVersions compares version `againstID` (old) with version `versionID` (new)
+// of the same article.
+func (s *articleService) DiffVersions(articleID, versionID, againstID uint, user authz.User) (*models.VersionDiff, error) {
+	article, err := s.articleRepo.GetByID(articleID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !authz.Can(user, authz.ActionView, article) {
+		return nil, authz.ErrUnauthorized
+	}
+
+	from, err := s.articleRepo.GetVersion(articleID, againstID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get version %d: %w", againstID, err)
+	}
+
+	to, err := s.articleRepo.GetVersion(articleID, versionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get version %d: %w", versionID, err)
+	}
+
+	oldLines := splitLines(from.Content)
+	newLines := splitLines(to.Content)
+	hunks := buildDiffHunks(oldLines, newLines, helper.Diff(oldLines, newLines))
+
+	diff := &models.VersionDiff{
+		ArticleID: articleID,
+		From:      models.VersionRef{ID: from.ID, VersionNumber: from.VersionNumber, Title: from.Title},
+		To:        models.VersionRef{ID: to.ID, VersionNumber: to.VersionNumber, Title: to.Title},
+		Title: models.TitleDiff{
+			Changed: from.Title != to.Title,
+			Old:     from.Title,
+			New:     to.Title,
+		},
+		Tags:  diffTags(from.Tags, to.Tags),
+		Hunks: hunks,
+	}
+
+	if diff.Title.Changed {
+		diff.Title.Words = diffWords(from.Title, to.Title)
+	}
+
+	diff.Unified = renderUnifiedDiff(
+		fmt.Sprintf("a/versions/%d (v%d)", from.ID, from.VersionNumber),
+		fmt.Sprintf("b/versions/%d (v%d)", to.ID, to.VersionNumber),
+		hunks,
+	)
+
+	return diff, nil
+}
+
+func splitLines(content string) []string {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	if content == "" {
+		return nil
+	}
+	return strings.Split(content, "\n")
+}
+
+func diffTags(oldTags, newTags []models.Tag) models.TagDiff {
+	oldSet := make(map[string]bool, len(oldTags))
+	for _, t := range oldTags {
+		oldSet[t.Name] = true
+	}
+	newSet := make(map[string]bool, len(newTags))
+	for _, t := range newTags {
+		newSet[t.Name] = true
+	}
+
+	result := models.TagDiff{
+		Added:     []string{},
+		Removed:   []string{},
+		Unchanged: []string{},
+	}
+	for name := range newSet {
+		if oldSet[name] {
+			result.Unchanged = append(result.Unchanged, name)
+		} else {
+			result.Added = append(result.Added, name)
+		}
+	}
+	for name := range oldSet {
+		if !newSet[name] {
+			result.Removed = append(result.Removed, name)
+		}
+	}
+
+	sort.Strings(result.Added)
+	sort.Strings(result.Removed)
+	sort.Strings(result.Unchanged)
+
+	return result
+}
+
+// diffWords returns a single combined word-level diff between two strings.
+func diffWords(oldText, newText string) []models.DiffSegment {
+	oldWords := helper.SplitWords(oldText)
+	newWords := helper.SplitWords(newText)
+
+	var segments []models.DiffSegment
+	for _, e := range helper.Diff(oldWords, newWords) {
+		switch e.Op {
+		case helper.EditEqual:
+			segments = appendSegment(segments, models.DiffEqual, oldWords[e.OldIndex])
+		case helper.EditDelete:
+			segments = appendSegment(segments, models.DiffDelete, oldWords[e.OldIndex])
+		case helper.EditInsert:
+			segments = appendSegment(segments, models.DiffInsert, newWords[e.NewIndex])
+		}
+	}
+	return segments
+}
+
+func appendSegment(segments []models.DiffSegment, op models.DiffOp, text string) []models.DiffSegment {
+	if n := len(segments); n > 0 && segments[n-1].Op == op {
+		segments[n-1].Text += text
+		return segments
+	}
+	return append(segments, models.DiffSegment{Op: op, Text: text})
+}
+
+// buildDiffHunks mengelompokkan edit script menjadi hunk dengan konteks
+// diffContextLines baris, seperti format unified diff.
+func buildDiffHunks(oldLines, newLines []string, edits []helper.Edit) []models.DiffHunk {
+	hunks := []models.DiffHunk{}
+
+	var changes []int
+	for i, e := range edits {
+		if e.Op != helper.EditEqual {
+			changes = append(changes, i)
+		}
+	}
+	if len(changes) == 0 {
+		return hunks
+	}
+
+	// Gabungkan perubahan yang jaraknya masih dalam jangkauan konteks
+	type span struct{ start, end int }
+	var spans []span
+	current := span{start: changes[0], end: changes[0]}
+	for _, idx := range changes[1:] {
+		if idx-current.end-1 <= 2*diffContextLines {
+			current.end = idx
+			continue
+		}
+		spans = append(spans, current)
+		current = span{start: idx, end: idx}
+	}
+	spans = append(spans, current)
+
+	for _, sp := range spans {
+		start := sp.start - diffContextLines
+		if start < 0 {
+			start = 0
+		}
+		end := sp.end + diffContextLines + 1
+		if end > len(edits) {
+			end = len(edits)
+		}
+
+		// Hitung posisi baris sebelum hunk dimulai
+		oldPos, newPos := 0, 0
+		for _, e := range edits[:start] {
+			if e.Op != helper.EditInsert {
+				oldPos++
+			}
+			if e.Op != helper.EditDelete {
+				newPos++
+			}
+		}
+
+		hunk := models.DiffHunk{}
+		for _, e := range edits[start:end] {
+			switch e.Op {
+			case helper.EditEqual:
+				hunk.OldLines++
+				hunk.NewLines++
+				hunk.Lines = append(hunk.Lines, models.DiffLine{
+					Op:        models.DiffEqual,
+					OldNumber: e.OldIndex + 1,
+					NewNumber: e.NewIndex + 1,
+					Text:      oldLines[e.OldIndex],
+				})
+			case helper.EditDelete:
+				hunk.OldLines++
+				hunk.Lines = append(hunk.Lines, models.DiffLine{
+					Op:        models.DiffDelete,
+					OldNumber: e.OldIndex + 1,
+					Text:      oldLines[e.OldIndex],
+				})
+			case helper.EditInsert:
+				hunk.NewLines++
+				hunk.Lines = append(hunk.Lines, models.DiffLine{
+					Op:        models.DiffInsert,
+					NewNumber: e.NewIndex + 1,
+					Text:      newLines[e.NewIndex],
+				})
+			}
+		}
+
+		hunk.OldStart = oldPos
+		if hunk.OldLines > 0 {
+			hunk.OldStart++
+		}
+		hunk.NewStart = newPos
+		if hunk.NewLines > 0 {
+			hunk.NewStart++
+		}
+
+		attachWordDiffs(hunk.Lines)
+		hunks = append(hunks, hunk)
+	}
+
+	return hunks
+}
+
+// attachWordDiffs pairs each run of deleted lines with the inserted lines that
+// directly follow it and records which words changed on both sides.
+func attachWordDiffs(lines []models.DiffLine) {
+	i := 0
+	for i < len(lines) {
+		if lines[i].Op != models.DiffDelete {
+			i++
+			continue
+		}
+
+		delStart := i
+		for i < len(lines) && lines[i].Op == models.DiffDelete {
+			i++
+		}
+		insStart := i
+		for i < len(lines) && lines[i].Op == models.DiffInsert {
+			i++
+		}
+
+		pairs := insStart - delStart
+		if i-insStart < pairs {
+			pairs = i - insStart
+		}
+
+		for p := 0; p < pairs; p++ {
+			oldLine := &lines[delStart+p]
+			newLine := &lines[insStart+p]
+			for _, seg := range diffWords(oldLine.Text, newLine.Text) {
+				if seg.Op != models.DiffInsert {
+					oldLine.Words = append(oldLine.Words, seg)
+				}
+				if seg.Op != models.DiffDelete {
+					newLine.Words = append(newLine.Words, seg)
+				}
+			}
+		}
+	}
+}
+
+func renderUnifiedDiff(fromLabel, toLabel string, hunks []models.DiffHunk) string {
+	if len(hunks) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", fromLabel, toLabel)
+	for _, h := range hunks {
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n", unifiedRange(h.OldStart, h.OldLines), unifiedRange(h.NewStart, h.NewLines))
+		for _, l := range h.Lines {
+			switch l.Op {
+			case models.DiffEqual:
+				sb.WriteString(" ")
+			case models.DiffDelete:
+				sb.WriteString("-")
+			case models.DiffInsert:
+				sb.WriteString("+")
+			}
+			sb.WriteString(l.Text)
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String()
+}
+
+func unifiedRange(start, count int) string {
+	if count == 1 {
+		return fmt.Sprintf("%d", start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
@@ -0,0 +1,74 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"cisdi-test-cms/models"
+	"cisdi-test-cms/repositories"
+)
+
+// ErrPreconditionRequired dikembalikan jika request yang mengubah versi tidak
+// menyertakan If-Match maupun base_version_id.
+var ErrPreconditionRequired = errors.New("If-Match header or base_version_id is required")
+
+// StaleVersionError menandakan request dibuat dari versi artikel yang sudah
+// tidak terbaru. Latest berisi latest version saat ini.
+type StaleVersionError struct {
+	Latest *models.ArticleVersion
+	ETag   string
+}
+
+func (e *StaleVersionError) Error() string {
+	return fmt.Sprintf("article has been modified, latest version is %d", e.Latest.ID)
+}
+
+// ArticleETag menurunkan ETag artikel dari latest version dan waktu update
+// terakhir. applyVersionStatus menggeser updated_at di setiap perubahan status
+// (termasuk review yang tidak publish/unpublish), sehingga ETag berubah setiap
+// ada versi baru maupun perubahan status.
+func ArticleETag(article *models.Article) string {
+	return fmt.Sprintf(`"%d-%d"`, article.LatestVersionID, article.UpdatedAt.UnixMicro())
+}
+
+// checkVersionPrecondition mencocokkan If-Match/base_version_id dengan kondisi
+// artikel saat ini. Jika required false, precondition hanya dicek bila dikirim.
+func checkVersionPrecondition(articleRepo repositories.ArticleRepository, article *models.Article, ifMatch string, baseVersionID *uint, required bool) error {
+	ifMatch = strings.TrimSpace(ifMatch)
+	if ifMatch == "" && baseVersionID == nil {
+		if required {
+			return ErrPreconditionRequired
+		}
+		return nil
+	}
+
+	fresh := true
+	if ifMatch != "" && !etagMatches(ifMatch, ArticleETag(article)) {
+		fresh = false
+	}
+	if baseVersionID != nil && *baseVersionID != article.LatestVersionID {
+		fresh = false
+	}
+	if fresh {
+		return nil
+	}
+
+	latest, err := articleRepo.GetVersionByID(article.LatestVersionID)
+	if err != nil {
+		return fmt.Errorf("failed to get latest version: %w", err)
+	}
+	return &StaleVersionError{Latest: latest, ETag: ArticleETag(article)}
+}
+
+// etagMatches mengikuti perbandingan If-Match (RFC 7232): "*" cocok dengan
+// artikel apa pun, selain itu salah satu ETag di daftar harus sama persis.
+func etagMatches(ifMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,204 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"cisdi-test-cms/authz"
+	"cisdi-test-cms/models"
+	"cisdi-test-cms/repositories"
+)
+
+// ScheduleVersion sets publish_at and/or unpublish_at on a version. The actual
+// status change is done later by the scheduler through RunScheduledPublishes
+// and RunScheduledUnpublishes.
+func (s *articleService) ScheduleVersion(articleID, versionID uint, req models.ScheduleVersionRequest, user authz.User) (*models.ArticleVersion, error) {
+	if req.PublishAt == nil && req.UnpublishAt == nil {
+		return nil, errors.New("publish_at or unpublish_at is required")
+	}
+
+	article, err := s.articleRepo.GetByID(articleID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !authz.Can(user, authz.ActionChangeStatus, article) {
+		return nil, authz.ErrUnauthorized
+	}
+
+	version, err := s.articleRepo.GetVersion(articleID, versionID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Kolom TIMESTAMP tidak menyimpan zona waktu (pgx membacanya sebagai UTC),
+	// jadi semua jadwal disimpan dan dibandingkan dalam UTC
+	now := time.Now().UTC()
+	updates := map[string]interface{}{}
+
+	if req.PublishAt != nil {
+		publishAt := req.PublishAt.UTC()
+		if !publishAt.After(now) {
+			return nil, errors.New("publish_at must be in the future")
+		}
+		// Jadwal publish tunduk pada workflow review yang sama dengan publish manual
+		if err := checkVersionTransition(version.Status, models.StatusPublished, authz.Can(user, authz.ActionReview, article), ""); err != nil {
+			return nil, err
+		}
+		updates["publish_at"] = publishAt
+		version.PublishAt = &publishAt
+	}
+
+	if req.UnpublishAt != nil {
+		unpublishAt := req.UnpublishAt.UTC()
+		if !unpublishAt.After(now) {
+			return nil, errors.New("unpublish_at must be in the future")
+		}
+		if version.PublishAt != nil {
+			if !unpublishAt.After(*version.PublishAt) {
+				return nil, errors.New("unpublish_at must be after publish_at")
+			}
+		} else if version.Status != models.StatusPublished {
+			return nil, errors.New("only published or scheduled versions can be unpublished")
+		}
+		updates["unpublish_at"] = unpublishAt
+	}
+
+	if err := s.articleRepo.UpdateVersion(version.ID, updates); err != nil {
+		return nil, err
+	}
+
+	return s.articleRepo.GetVersionByID(version.ID)
+}
+
+func (s *articleService) CancelVersionSchedule(articleID, versionID uint, user authz.User) error {
+	article, err := s.articleRepo.GetByID(articleID)
+	if err != nil {
+		return err
+	}
+
+	if !authz.Can(user, authz.ActionChangeStatus, article) {
+		return authz.ErrUnauthorized
+	}
+
+	version, err := s.articleRepo.GetVersion(articleID, versionID)
+	if err != nil {
+		return err
+	}
+
+	if version.PublishAt == nil && version.UnpublishAt == nil {
+		return errors.New("version has no pending schedule")
+	}
+
+	return s.articleRepo.UpdateVersion(version.ID, map[string]interface{}{
+		"publish_at":   nil,
+		"unpublish_at": nil,
+	})
+}
+
+func (s *articleService) GetPendingSchedules(user authz.User) ([]models.ArticleVersion, error) {
+	// Editor dan admin melihat jadwal semua penulis
+	if authz.Can(user, authz.ActionChangeStatus, nil) {
+		return s.articleVersionRepo.GetPendingSchedules(0)
+	}
+	return s.articleVersionRepo.GetPendingSchedules(user.ID)
+}
+
+// RunScheduledPublishes publishes every version whose publish_at has passed,
+// archiving the previously published version exactly like UpdateVersionStatus.
+func (s *articleService) RunScheduledPublishes(now time.Time) error {
+	versions, err := s.articleVersionRepo.GetDueScheduledPublishes(now.UTC())
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, due := range versions {
+		if err := s.runScheduledStatus(due, models.StatusPublished, now.UTC()); err != nil {
+			errs = append(errs, fmt.Errorf("failed to publish version %d: %w", due.ID, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// RunScheduledUnpublishes archives every published version whose unpublish_at has passed.
+func (s *articleService) RunScheduledUnpublishes(now time.Time) error {
+	versions, err := s.articleVersionRepo.GetDueScheduledUnpublishes(now.UTC())
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, due := range versions {
+		if err := s.runScheduledStatus(due, models.StatusArchivedVersion, now.UTC()); err != nil {
+			errs = append(errs, fmt.Errorf("failed to unpublish version %d: %w", due.ID, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// runScheduledStatus menjalankan satu jadwal publish (status published) atau
+// unpublish (status archived_version) dalam satu unit of work dengan artikel
+// terkunci, sama seperti UpdateVersionStatus, sehingga aman dijalankan
+// bersamaan dengan request user maupun replica lain. Versi dibaca ulang setelah
+// artikel terkunci; jadwal yang sudah dibatalkan dilewati, dan jadwal yang
+// transisinya tidak lagi valid dihapus tanpa mengubah status.
+func (s *articleService) runScheduledStatus(due models.ArticleVersion, status models.VersionStatus, now time.Time) error {
+	scheduleColumn := "publish_at"
+	if status != models.StatusPublished {
+		scheduleColumn = "unpublish_at"
+	}
+
+	var publicationChanged bool
+	err := s.uow.Do(func(repos repositories.Repositories) error {
+		locked, err := repos.Articles.LockForUpdate(due.ArticleID)
+		if err != nil {
+			return err
+		}
+		version, err := repos.Articles.GetVersion(due.ArticleID, due.ID)
+		if err != nil {
+			return err
+		}
+
+		scheduledAt := version.PublishAt
+		if status != models.StatusPublished {
+			scheduledAt = version.UnpublishAt
+		}
+		if scheduledAt == nil || scheduledAt.After(now) {
+			return nil
+		}
+
+		// Setiap perubahan status menghapus jadwal, jadi status versi masih sama
+		// dengan saat dijadwalkan dan hak reviewer sudah dicek oleh ScheduleVersion.
+		// Transisi tetap dicek ulang agar jadwal yang tidak valid tidak dijalankan.
+		err = checkVersionTransition(version.Status, status, true, "")
+		if err == nil && status != models.StatusPublished && version.Status != models.StatusPublished {
+			err = fmt.Errorf("version is %s, not published", version.Status)
+		}
+		if err != nil {
+			log.Printf("scheduler: dropped %s of version %d: %v", scheduleColumn, version.ID, err)
+			return repos.Articles.UpdateVersion(version.ID, map[string]interface{}{
+				scheduleColumn: nil,
+			})
+		}
+
+		publicationChanged, err = s.applyVersionStatus(repos, locked, version, status, 0)
+		if err != nil {
+			return err
+		}
+		log.Printf("scheduler: changed version %d of article %d to %s", version.ID, version.ArticleID, status)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if publicationChanged {
+		s.notifyPublicationChanged()
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"cisdi-test-cms/authz"
+	"cisdi-test-cms/models"
+)
+
+type transitionRule struct {
+	reviewerOnly    bool // hanya editor/admin
+	commentRequired bool
+}
+
+// versionTransitions adalah tabel transisi status versi yang diizinkan.
+// Writer mengajukan review (draft -> in_review), editor menyetujui atau meminta
+// perubahan, dan hanya versi approved yang boleh dipublish oleh writer.
+var versionTransitions = map[models.VersionStatus]map[models.VersionStatus]transitionRule{
+	models.StatusDraft: {
+		models.StatusInReview:        {},
+		models.StatusPublished:       {reviewerOnly: true},
+		models.StatusArchivedVersion: {},
+	},
+	models.StatusInReview: {
+		models.StatusApproved:         {reviewerOnly: true},
+		models.StatusChangesRequested: {reviewerOnly: true, commentRequired: true},
+		models.StatusPublished:        {reviewerOnly: true},
+		models.StatusDraft:            {},
+	},
+	models.StatusChangesRequested: {
+		models.StatusInReview:        {},
+		models.StatusDraft:           {},
+		models.StatusArchivedVersion: {},
+	},
+	models.StatusApproved: {
+		models.StatusPublished:        {},
+		models.StatusChangesRequested: {reviewerOnly: true, commentRequired: true},
+		models.StatusDraft:            {},
+	},
+	models.StatusPublished: {
+		models.StatusArchivedVersion: {},
+		models.StatusDraft:           {},
+	},
+	models.StatusArchivedVersion: {
+		models.StatusPublished: {reviewerOnly: true},
+		models.StatusDraft:     {},
+	},
+}
+
+// checkVersionTransition memvalidasi transisi terhadap tabel workflow.
+// canReview diisi dari authz.ActionReview milik pemanggil.
+func checkVersionTransition(from, to models.VersionStatus, canReview bool, comment string) error {
+	if from == to {
+		return fmt.Errorf("version is already %s", to)
+	}
+
+	rule, ok := versionTransitions[from][to]
+	if !ok {
+		return fmt.Errorf("invalid status transition from %s to %s", from, to)
+	}
+
+	if rule.reviewerOnly && !canReview {
+		return fmt.Errorf("only editors or admins can change status from %s to %s", from, to)
+	}
+
+	if rule.commentRequired && strings.TrimSpace(comment) == "" {
+		return errors.New("comment is required when requesting changes")
+	}
+
+	return nil
+}
+
+func (s *articleService) GetVersionReviews(articleID, versionID uint, user authz.User) ([]models.VersionReview, error) {
+	article, err := s.articleRepo.GetByID(articleID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !authz.Can(user, authz.ActionView, article) {
+		return nil, authz.ErrUnauthorized
+	}
+
+	if _, err := s.articleRepo.GetVersion(articleID, versionID); err != nil {
+		return nil, err
+	}
+
+	return s.articleVersionRepo.GetReviews(versionID)
+}
@@ -6,17 +6,23 @@ import (
 	"fmt"
 	"io/ioutil"
 	"log"
+	"math"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/suite"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 
+	"cisdi-test-cms/config"
 	"cisdi-test-cms/handlers"
+	"cisdi-test-cms/helper"
 	"cisdi-test-cms/middleware"
 	"cisdi-test-cms/models"
 	"cisdi-test-cms/repositories"
@@ -25,10 +31,12 @@ import (
 
 type IntegrationTestSuite struct {
 	suite.Suite
-	db     *gorm.DB
-	router *gin.Engine
-	token  string
-	userID uint
+	db             *gorm.DB
+	router         *gin.Engine
+	token          string
+	userID         uint
+	articleService services.ArticleService
+	tagService     services.TagService
 }
 
 func (suite *IntegrationTestSuite) SetupSuite() {
@@ -65,20 +73,37 @@ func (suite *IntegrationTestSuite) setupRouter() {
 	articleRepo := repositories.NewArticleRepository(suite.db)
 	tagRepo := repositories.NewTagRepository(suite.db)
 	articleVersionRepo := repositories.NewArticleVersionRepository(suite.db)
+	unitOfWork := repositories.NewUnitOfWork(suite.db)
 
 	// Initialize services
 	authService := services.NewAuthService(userRepo)
-	articleService := services.NewArticleService(articleRepo, tagRepo, articleVersionRepo)
-	tagService := services.NewTagService(tagRepo, articleRepo)
+	contentRenderer := services.NewContentRenderer(config.ContentRenderCacheSize())
+	tagNormalizer := services.NewTagNormalizer(config.TagNameMaxLength(), config.TagCaseFolding(), config.TagDisallowedChars())
+	relationshipScorer, err := services.NewRelationshipScorer(config.RelationshipScorer())
+	suite.Require().NoError(err)
+	sitemapService := services.NewSitemapService(articleRepo, tagRepo, services.SitemapMaxURLs, config.SitemapCacheTTL())
+	articleService := services.NewArticleService(articleRepo, tagRepo, articleVersionRepo, unitOfWork, contentRenderer, tagNormalizer, relationshipScorer, sitemapService)
+	tagService := services.NewTagService(tagRepo, articleRepo, tagNormalizer, relationshipScorer)
+	tagTrendingService := services.NewTagTrendingService(articleRepo, tagRepo, config.TagTrendingHalfLife())
+	feedService := services.NewFeedService(articleRepo, contentRenderer, config.FeedItemLimit())
+	relatedArticleService := services.NewRelatedArticleService(articleRepo, contentRenderer, config.RelatedArticleLimit(), config.RelatedArticleHalfLife())
+	suite.articleService = articleService
+	suite.tagService = tagService
 
 	// Initialize handlers
 	authHandler := handlers.NewAuthHandler(authService)
 	articleHandler := handlers.NewArticleHandler(articleService)
-	tagHandler := handlers.NewTagHandler(tagService)
+	tagHandler := handlers.NewTagHandler(tagService, tagTrendingService)
+	feedHandler := handlers.NewFeedHandler(feedService)
+	relatedArticleHandler := handlers.NewRelatedArticleHandler(relatedArticleService)
+	sitemapHandler := handlers.NewSitemapHandler(sitemapService)
 
 	// Setup router
 	router := gin.New()
 
+	router.GET("/sitemap.xml", sitemapHandler.GetSitemap)
+	router.GET("/sitemaps/:name", sitemapHandler.GetSitemapPage)
+
 	v1 := router.Group("/api/v1")
 	{
 		// Auth routes
@@ -98,19 +123,45 @@ func (suite *IntegrationTestSuite) setupRouter() {
 			{
 				articles.POST("", articleHandler.CreateArticle)
 				articles.GET("", articleHandler.GetArticles)
+				articles.GET("/schedules", articleHandler.GetPendingSchedules)
+				articles.POST("/score-preview", articleHandler.PreviewScore)
 				articles.GET("/:id", articleHandler.GetArticle)
+				articles.PATCH("/:id", articleHandler.UpdateArticle)
 				articles.DELETE("/:id", articleHandler.DeleteArticle)
+				articles.GET("/:id/audits", articleHandler.GetArticleAudits)
 				articles.POST("/:id/versions", articleHandler.CreateArticleVersion)
 				articles.PUT("/:id/versions/:version_id/status", articleHandler.UpdateVersionStatus)
 				articles.GET("/:id/versions", articleHandler.GetArticleVersions)
 				articles.GET("/:id/versions/:version_id", articleHandler.GetArticleVersion)
+				articles.GET("/:id/versions/:version_id/diff", articleHandler.DiffArticleVersions)
+				articles.POST("/:id/versions/:version_id/revert", articleHandler.RevertArticleVersion)
+				articles.PUT("/:id/versions/:version_id/schedule", articleHandler.ScheduleVersion)
+				articles.DELETE("/:id/versions/:version_id/schedule", articleHandler.CancelVersionSchedule)
+				articles.GET("/:id/versions/:version_id/reviews", articleHandler.GetVersionReviews)
+			}
+
+			trash := protected.Group("/admin/trash/articles")
+			{
+				trash.GET("", articleHandler.GetTrashedArticles)
+				trash.POST("/:id/restore", articleHandler.RestoreArticle)
+				trash.DELETE("/:id/purge", articleHandler.PurgeArticle)
 			}
 
+			protected.POST("/admin/tags/recompute-trending", tagHandler.RecomputeTrending)
+
 			tags := protected.Group("/tags")
 			{
 				tags.POST("", tagHandler.CreateTag)
 				tags.GET("", tagHandler.GetTags)
+				tags.GET("/tree", tagHandler.GetTagTree)
+				tags.POST("/suggest", tagHandler.SuggestTags)
 				tags.GET("/:id", tagHandler.GetTag)
+				tags.PUT("/:id", tagHandler.RenameTag)
+				tags.DELETE("/:id", tagHandler.DeleteTag)
+				tags.POST("/:id/merge", tagHandler.MergeTag)
+				tags.PUT("/:id/parent", tagHandler.SetTagParent)
+				tags.POST("/:id/aliases", tagHandler.AddTagAlias)
+				tags.DELETE("/:id/aliases/:alias_id", tagHandler.RemoveTagAlias)
 			}
 		}
 
@@ -118,7 +169,12 @@ func (suite *IntegrationTestSuite) setupRouter() {
 		public := v1.Group("/public")
 		{
 			public.GET("/articles", articleHandler.GetPublicArticles)
+			public.GET("/articles/by-slug/:slug", articleHandler.GetPublicArticleBySlug)
 			public.GET("/articles/:id", articleHandler.GetPublicArticle)
+			public.GET("/articles/:id/related", relatedArticleHandler.GetRelatedArticles)
+			public.GET("/tags/trending", tagHandler.GetTrendingTags)
+			public.GET("/feed.rss", feedHandler.GetRSSFeed)
+			public.GET("/feed.atom", feedHandler.GetAtomFeed)
 		}
 	}
 
@@ -127,18 +183,36 @@ func (suite *IntegrationTestSuite) setupRouter() {
 
 func (suite *IntegrationTestSuite) TearDownSuite() {
 	// Clean up test database
-	suite.db.Exec("DROP TABLE IF EXISTS article_version_tags")
-	suite.db.Exec("DROP TABLE IF EXISTS article_versions")
-	suite.db.Exec("DROP TABLE IF EXISTS articles")
-	suite.db.Exec("DROP TABLE IF EXISTS tags")
-	suite.db.Exec("DROP TABLE IF EXISTS users")
+	dropTestTables(suite.db)
+}
+
+func dropTestTables(db *gorm.DB) {
+	db.Exec("DROP TABLE IF EXISTS version_reviews")
+	db.Exec("DROP TABLE IF EXISTS tag_daily_usage")
+	db.Exec("DROP TABLE IF EXISTS tag_pair_stats")
+	db.Exec("DROP TABLE IF EXISTS tag_stats")
+	db.Exec("DROP TABLE IF EXISTS article_version_tags")
+	db.Exec("DROP TABLE IF EXISTS article_versions")
+	db.Exec("DROP TABLE IF EXISTS article_audits")
+	db.Exec("DROP TABLE IF EXISTS article_slugs")
+	db.Exec("DROP TABLE IF EXISTS articles")
+	db.Exec("DROP TABLE IF EXISTS tag_aliases")
+	db.Exec("DROP TABLE IF EXISTS tags")
+	db.Exec("DROP TABLE IF EXISTS users")
 }
 
 func (suite *IntegrationTestSuite) SetupTest() {
 	// Clean all tables before each test
+	suite.db.Exec("TRUNCATE TABLE version_reviews RESTART IDENTITY CASCADE")
+	suite.db.Exec("TRUNCATE TABLE tag_daily_usage RESTART IDENTITY CASCADE")
+	suite.db.Exec("TRUNCATE TABLE tag_pair_stats RESTART IDENTITY CASCADE")
+	suite.db.Exec("TRUNCATE TABLE tag_stats RESTART IDENTITY CASCADE")
 	suite.db.Exec("TRUNCATE TABLE article_version_tags RESTART IDENTITY CASCADE")
 	suite.db.Exec("TRUNCATE TABLE article_versions RESTART IDENTITY CASCADE")
+	suite.db.Exec("TRUNCATE TABLE article_audits RESTART IDENTITY CASCADE")
+	suite.db.Exec("TRUNCATE TABLE article_slugs RESTART IDENTITY CASCADE")
 	suite.db.Exec("TRUNCATE TABLE articles RESTART IDENTITY CASCADE")
+	suite.db.Exec("TRUNCATE TABLE tag_aliases RESTART IDENTITY CASCADE")
 	suite.db.Exec("TRUNCATE TABLE tags RESTART IDENTITY CASCADE")
 	suite.db.Exec("TRUNCATE TABLE users RESTART IDENTITY CASCADE")
 
@@ -324,9 +398,10 @@ func (suite *IntegrationTestSuite) TestArticleVersioning() {
 
 	// Create new version
 	versionPayload := models.CreateArticleVersionRequest{
-		Title:   "Updated Article",
-		Content: "<p>Updated content</p>",
-		Tags:    []string{"version", "updated"},
+		Title:         "Updated Article",
+		Content:       "<p>Updated content</p>",
+		Tags:          []string{"version", "updated"},
+		BaseVersionID: &article.LatestVersionID,
 	}
 
 	body, _ = json.Marshal(versionPayload)
@@ -406,7 +481,8 @@ func (suite *IntegrationTestSuite) TestPublishArticle() {
 
 	// Publish version
 	publishPayload := models.UpdateVersionStatusRequest{
-		Status: models.StatusPublished,
+		Status:        models.StatusPublished,
+		BaseVersionID: &article.LatestVersionID,
 	}
 
 	body, _ = json.Marshal(publishPayload)
@@ -546,6 +622,2400 @@ func (suite *IntegrationTestSuite) TestArticleTagRelationshipScore() {
 	}
 }
 
+func (suite *IntegrationTestSuite) TestVersionDiff() {
+	createPayload := models.CreateArticleRequest{
+		Title:   "Diff Article",
+		Content: "line one\nline two\nline three",
+		Tags:    []string{"diff", "old-tag"},
+	}
+
+	body, _ := json.Marshal(createPayload)
+	req := httptest.NewRequest("POST", "/api/v1/articles", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+suite.token)
+
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	var createResp struct {
+		Data models.Article `json:"data"`
+	}
+	err := json.Unmarshal(w.Body.Bytes(), &createResp)
+	suite.NoError(err)
+	article := createResp.Data
+
+	versionPayload := models.CreateArticleVersionRequest{
+		Title:         "Diff Article Updated",
+		Content:       "line one\nline 2\nline three\nline four",
+		Tags:          []string{"diff", "new-tag"},
+		BaseVersionID: &article.LatestVersionID,
+	}
+
+	body, _ = json.Marshal(versionPayload)
+	req = httptest.NewRequest("POST", fmt.Sprintf("/api/v1/articles/%d/versions", article.ID), bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+suite.token)
+
+	w = httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	var versionResp struct {
+		Data models.ArticleVersion `json:"data"`
+	}
+	err = json.Unmarshal(w.Body.Bytes(), &versionResp)
+	suite.NoError(err)
+	version := versionResp.Data
+
+	// Diff versi baru terhadap versi pertama
+	req = httptest.NewRequest("GET", fmt.Sprintf("/api/v1/articles/%d/versions/%d/diff?against=%d", article.ID, version.ID, article.LatestVersionID), nil)
+	req.Header.Set("Authorization", "Bearer "+suite.token)
+
+	w = httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusOK, w.Code)
+
+	var diffResp struct {
+		Data models.VersionDiff `json:"data"`
+	}
+	err = json.Unmarshal(w.Body.Bytes(), &diffResp)
+	suite.NoError(err)
+	diff := diffResp.Data
+
+	suite.True(diff.Title.Changed)
+	suite.Equal([]string{"new-tag"}, diff.Tags.Added)
+	suite.Equal([]string{"old-tag"}, diff.Tags.Removed)
+	suite.Len(diff.Hunks, 1)
+	suite.Contains(diff.Unified, "-line two\n+line 2\n")
+	suite.Contains(diff.Unified, "+line four\n")
+
+	// Parameter against wajib diisi
+	req = httptest.NewRequest("GET", fmt.Sprintf("/api/v1/articles/%d/versions/%d/diff", article.ID, version.ID), nil)
+	req.Header.Set("Authorization", "Bearer "+suite.token)
+
+	w = httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusBadRequest, w.Code)
+}
+
+func (suite *IntegrationTestSuite) TestRevertArticleVersion() {
+	createPayload := models.CreateArticleRequest{
+		Title:   "Revert Article",
+		Content: "<p>Good content</p>",
+		Tags:    []string{"revert", "good"},
+	}
+
+	body, _ := json.Marshal(createPayload)
+	req := httptest.NewRequest("POST", "/api/v1/articles", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+suite.token)
+
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	var createResp struct {
+		Data models.Article `json:"data"`
+	}
+	err := json.Unmarshal(w.Body.Bytes(), &createResp)
+	suite.NoError(err)
+	article := createResp.Data
+
+	// Versi kedua berisi edit yang salah
+	versionPayload := models.CreateArticleVersionRequest{
+		Title:         "Revert Article (bad edit)",
+		Content:       "<p>Bad content</p>",
+		Tags:          []string{"revert", "bad"},
+		BaseVersionID: &article.LatestVersionID,
+	}
+
+	body, _ = json.Marshal(versionPayload)
+	req = httptest.NewRequest("POST", fmt.Sprintf("/api/v1/articles/%d/versions", article.ID), bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+suite.token)
+
+	w = httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusOK, w.Code)
+
+	// Revert tanpa If-Match/base_version_id ditolak
+	req = httptest.NewRequest("POST", fmt.Sprintf("/api/v1/articles/%d/versions/%d/revert", article.ID, article.LatestVersionID), nil)
+	req.Header.Set("Authorization", "Bearer "+suite.token)
+
+	w = httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusPreconditionRequired, w.Code)
+
+	// base_version_id yang sudah bukan latest version ditolak dengan 412
+	body, _ = json.Marshal(models.RevertArticleVersionRequest{BaseVersionID: &article.LatestVersionID})
+	req = httptest.NewRequest("POST", fmt.Sprintf("/api/v1/articles/%d/versions/%d/revert", article.ID, article.LatestVersionID), bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+suite.token)
+
+	w = httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusPreconditionFailed, w.Code)
+
+	// Revert ke versi pertama
+	req = httptest.NewRequest("POST", fmt.Sprintf("/api/v1/articles/%d/versions/%d/revert", article.ID, article.LatestVersionID), nil)
+	req.Header.Set("Authorization", "Bearer "+suite.token)
+	req.Header.Set("If-Match", suite.articleETag(suite.token, article.ID))
+
+	w = httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusOK, w.Code)
+
+	var revertResp struct {
+		Data models.ArticleVersion `json:"data"`
+	}
+	err = json.Unmarshal(w.Body.Bytes(), &revertResp)
+	suite.NoError(err)
+	reverted := revertResp.Data
+
+	suite.Equal(3, reverted.VersionNumber)
+	suite.Equal(models.StatusDraft, reverted.Status)
+	suite.Equal("Revert Article", reverted.Title)
+	suite.Equal("<p>Good content</p>", reverted.Content)
+	suite.Len(reverted.Tags, 2)
+	suite.Require().NotNil(reverted.RevertedFromVersionID)
+	suite.Equal(article.LatestVersionID, *reverted.RevertedFromVersionID)
+
+	// Latest version artikel harus menunjuk ke versi hasil revert
+	req = httptest.NewRequest("GET", fmt.Sprintf("/api/v1/articles/%d", article.ID), nil)
+	req.Header.Set("Authorization", "Bearer "+suite.token)
+
+	w = httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	var getResp struct {
+		Data models.Article `json:"data"`
+	}
+	err = json.Unmarshal(w.Body.Bytes(), &getResp)
+	suite.NoError(err)
+	suite.Equal(reverted.ID, getResp.Data.LatestVersionID)
+}
+
+func (suite *IntegrationTestSuite) TestScheduledPublishing() {
+	createPayload := models.CreateArticleRequest{
+		Title:   "Scheduled Article",
+		Content: "<p>Scheduled content</p>",
+		Tags:    []string{"schedule", "test"},
+	}
+
+	body, _ := json.Marshal(createPayload)
+	req := httptest.NewRequest("POST", "/api/v1/articles", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+suite.token)
+
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	var createResp struct {
+		Data models.Article `json:"data"`
+	}
+	err := json.Unmarshal(w.Body.Bytes(), &createResp)
+	suite.NoError(err)
+	article := createResp.Data
+
+	// Jadwalkan publish 1 jam lagi dan unpublish 2 jam lagi
+	publishAt := time.Now().Add(time.Hour)
+	unpublishAt := time.Now().Add(2 * time.Hour)
+	schedulePayload := models.ScheduleVersionRequest{
+		PublishAt:   &publishAt,
+		UnpublishAt: &unpublishAt,
+	}
+
+	body, _ = json.Marshal(schedulePayload)
+	req = httptest.NewRequest("PUT", fmt.Sprintf("/api/v1/articles/%d/versions/%d/schedule", article.ID, article.LatestVersionID), bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+suite.token)
+
+	w = httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusOK, w.Code)
+
+	// Jadwal muncul di list pending
+	req = httptest.NewRequest("GET", "/api/v1/articles/schedules", nil)
+	req.Header.Set("Authorization", "Bearer "+suite.token)
+
+	w = httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	var schedulesResp struct {
+		Data []models.ArticleVersion `json:"data"`
+	}
+	err = json.Unmarshal(w.Body.Bytes(), &schedulesResp)
+	suite.NoError(err)
+	suite.Len(schedulesResp.Data, 1)
+
+	// Belum jatuh tempo, artikel belum publik
+	suite.NoError(suite.articleService.RunScheduledPublishes(time.Now()))
+
+	req = httptest.NewRequest("GET", fmt.Sprintf("/api/v1/public/articles/%d", article.ID), nil)
+	w = httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.NotEqual(http.StatusOK, w.Code)
+
+	// Setelah publish_at lewat, versi dipublish oleh job
+	suite.NoError(suite.articleService.RunScheduledPublishes(time.Now().Add(90 * time.Minute)))
+
+	req = httptest.NewRequest("GET", fmt.Sprintf("/api/v1/public/articles/%d", article.ID), nil)
+	w = httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusOK, w.Code)
+
+	// Setelah unpublish_at lewat, versi diarsipkan
+	suite.NoError(suite.articleService.RunScheduledUnpublishes(time.Now().Add(3 * time.Hour)))
+
+	req = httptest.NewRequest("GET", fmt.Sprintf("/api/v1/public/articles/%d", article.ID), nil)
+	w = httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.NotEqual(http.StatusOK, w.Code)
+
+	req = httptest.NewRequest("GET", "/api/v1/articles/schedules", nil)
+	req.Header.Set("Authorization", "Bearer "+suite.token)
+
+	w = httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	err = json.Unmarshal(w.Body.Bytes(), &schedulesResp)
+	suite.NoError(err)
+	suite.Len(schedulesResp.Data, 0)
+}
+
+func (suite *IntegrationTestSuite) TestCancelVersionSchedule() {
+	createPayload := models.CreateArticleRequest{
+		Title:   "Cancelled Schedule",
+		Content: "<p>Content</p>",
+	}
+
+	body, _ := json.Marshal(createPayload)
+	req := httptest.NewRequest("POST", "/api/v1/articles", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+suite.token)
+
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	var createResp struct {
+		Data models.Article `json:"data"`
+	}
+	err := json.Unmarshal(w.Body.Bytes(), &createResp)
+	suite.NoError(err)
+	article := createResp.Data
+
+	publishAt := time.Now().Add(time.Hour)
+	body, _ = json.Marshal(models.ScheduleVersionRequest{PublishAt: &publishAt})
+	req = httptest.NewRequest("PUT", fmt.Sprintf("/api/v1/articles/%d/versions/%d/schedule", article.ID, article.LatestVersionID), bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+suite.token)
+
+	w = httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusOK, w.Code)
+
+	req = httptest.NewRequest("DELETE", fmt.Sprintf("/api/v1/articles/%d/versions/%d/schedule", article.ID, article.LatestVersionID), nil)
+	req.Header.Set("Authorization", "Bearer "+suite.token)
+
+	w = httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusOK, w.Code)
+
+	// Job tidak boleh mempublish versi yang jadwalnya sudah dibatalkan
+	suite.NoError(suite.articleService.RunScheduledPublishes(time.Now().Add(2 * time.Hour)))
+
+	req = httptest.NewRequest("GET", fmt.Sprintf("/api/v1/public/articles/%d", article.ID), nil)
+	w = httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.NotEqual(http.StatusOK, w.Code)
+}
+
+func (suite *IntegrationTestSuite) registerUser(username, email string, role models.UserRole) (string, uint) {
+	registerPayload := models.RegisterRequest{
+		Username: username,
+		Email:    email,
+		Password: "password123",
+		Role:     role,
+	}
+
+	body, _ := json.Marshal(registerPayload)
+	req := httptest.NewRequest("POST", "/api/v1/auth/register", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Require().Equal(http.StatusOK, w.Code)
+
+	var registerResp struct {
+		Data models.AuthResponse `json:"data"`
+	}
+	err := json.Unmarshal(w.Body.Bytes(), &registerResp)
+	suite.Require().NoError(err)
+
+	return registerResp.Data.Token, registerResp.Data.User.ID
+}
+
+// articleETag mengambil ETag artikel untuk header If-Match, kosong jika
+// artikel tidak bisa diakses oleh token tersebut.
+func (suite *IntegrationTestSuite) articleETag(token string, articleID uint) string {
+	req := httptest.NewRequest("GET", fmt.Sprintf("/api/v1/articles/%d", articleID), nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	return w.Header().Get("ETag")
+}
+
+func (suite *IntegrationTestSuite) updateVersionStatus(token string, articleID, versionID uint, status models.VersionStatus, comment string) int {
+	etag := suite.articleETag(token, articleID)
+
+	body, _ := json.Marshal(models.UpdateVersionStatusRequest{Status: status, Comment: comment})
+	req := httptest.NewRequest("PUT", fmt.Sprintf("/api/v1/articles/%d/versions/%d/status", articleID, versionID), bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	if etag != "" {
+		req.Header.Set("If-Match", etag)
+	}
+
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	return w.Code
+}
+
+func (suite *IntegrationTestSuite) TestReviewWorkflow() {
+	writerToken, _ := suite.registerUser("writer", "writer@example.com", models.RoleWriter)
+	editorToken, _ := suite.registerUser("editor", "editor@example.com", models.RoleEditor)
+
+	createPayload := models.CreateArticleRequest{
+		Title:   "Reviewed Article",
+		Content: "<p>Needs review</p>",
+		Tags:    []string{"review"},
+	}
+
+	body, _ := json.Marshal(createPayload)
+	req := httptest.NewRequest("POST", "/api/v1/articles", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+writerToken)
+
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	var createResp struct {
+		Data models.Article `json:"data"`
+	}
+	err := json.Unmarshal(w.Body.Bytes(), &createResp)
+	suite.NoError(err)
+	article := createResp.Data
+	versionID := article.LatestVersionID
+
+	// Writer tidak boleh langsung publish draft
+	suite.Equal(http.StatusBadRequest, suite.updateVersionStatus(writerToken, article.ID, versionID, models.StatusPublished, ""))
+
+	// Writer mengajukan review
+	suite.Equal(http.StatusOK, suite.updateVersionStatus(writerToken, article.ID, versionID, models.StatusInReview, ""))
+
+	// Writer tidak boleh meng-approve sendiri
+	suite.Equal(http.StatusBadRequest, suite.updateVersionStatus(writerToken, article.ID, versionID, models.StatusApproved, ""))
+
+	// Request changes wajib menyertakan komentar
+	suite.Equal(http.StatusBadRequest, suite.updateVersionStatus(editorToken, article.ID, versionID, models.StatusChangesRequested, ""))
+	suite.Equal(http.StatusOK, suite.updateVersionStatus(editorToken, article.ID, versionID, models.StatusChangesRequested, "Please add sources"))
+
+	// Writer submit ulang, editor approve, lalu writer publish
+	suite.Equal(http.StatusOK, suite.updateVersionStatus(writerToken, article.ID, versionID, models.StatusInReview, ""))
+	suite.Equal(http.StatusOK, suite.updateVersionStatus(editorToken, article.ID, versionID, models.StatusApproved, ""))
+	suite.Equal(http.StatusOK, suite.updateVersionStatus(writerToken, article.ID, versionID, models.StatusPublished, ""))
+
+	req = httptest.NewRequest("GET", fmt.Sprintf("/api/v1/public/articles/%d", article.ID), nil)
+	w = httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusOK, w.Code)
+
+	// Riwayat review tercatat lengkap dengan komentar editor
+	req = httptest.NewRequest("GET", fmt.Sprintf("/api/v1/articles/%d/versions/%d/reviews", article.ID, versionID), nil)
+	req.Header.Set("Authorization", "Bearer "+writerToken)
+
+	w = httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusOK, w.Code)
+
+	var reviewsResp struct {
+		Data []models.VersionReview `json:"data"`
+	}
+	err = json.Unmarshal(w.Body.Bytes(), &reviewsResp)
+	suite.NoError(err)
+	suite.Require().Len(reviewsResp.Data, 5)
+	suite.Equal(models.StatusChangesRequested, reviewsResp.Data[1].ToStatus)
+	suite.Equal("Please add sources", reviewsResp.Data[1].Comment)
+	suite.Equal(models.StatusPublished, reviewsResp.Data[4].ToStatus)
+}
+
+func (suite *IntegrationTestSuite) TestRoleBasedArticleAccess() {
+	writerToken, _ := suite.registerUser("writer", "writer@example.com", models.RoleWriter)
+	otherWriterToken, _ := suite.registerUser("writer2", "writer2@example.com", models.RoleWriter)
+	editorToken, _ := suite.registerUser("editor", "editor@example.com", models.RoleEditor)
+
+	createPayload := models.CreateArticleRequest{
+		Title:   "Writer Article",
+		Content: "<p>Owned by writer</p>",
+	}
+
+	body, _ := json.Marshal(createPayload)
+	req := httptest.NewRequest("POST", "/api/v1/articles", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+writerToken)
+
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	var createResp struct {
+		Data models.Article `json:"data"`
+	}
+	err := json.Unmarshal(w.Body.Bytes(), &createResp)
+	suite.NoError(err)
+	article := createResp.Data
+
+	get := func(token string) int {
+		req := httptest.NewRequest("GET", fmt.Sprintf("/api/v1/articles/%d", article.ID), nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		suite.router.ServeHTTP(w, req)
+		return w.Code
+	}
+	del := func(token string) int {
+		req := httptest.NewRequest("DELETE", fmt.Sprintf("/api/v1/articles/%d", article.ID), nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		suite.router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// Writer lain tidak boleh melihat, editor boleh
+	suite.Equal(http.StatusOK, get(writerToken))
+	suite.NotEqual(http.StatusOK, get(otherWriterToken))
+	suite.Equal(http.StatusOK, get(editorToken))
+
+	// Editor boleh membuat versi pada artikel writer
+	versionPayload := models.CreateArticleVersionRequest{
+		Title:         "Writer Article (edited)",
+		Content:       "<p>Edited by editor</p>",
+		BaseVersionID: &article.LatestVersionID,
+	}
+	body, _ = json.Marshal(versionPayload)
+	req = httptest.NewRequest("POST", fmt.Sprintf("/api/v1/articles/%d/versions", article.ID), bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+editorToken)
+
+	w = httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusOK, w.Code)
+
+	// Editor tidak boleh menghapus artikel orang lain, admin boleh
+	suite.NotEqual(http.StatusOK, del(editorToken))
+	suite.NotEqual(http.StatusOK, del(otherWriterToken))
+	suite.Equal(http.StatusOK, del(suite.token))
+}
+
+func (suite *IntegrationTestSuite) TestFullTextSearch() {
+	articles := []models.CreateArticleRequest{
+		{
+			Title:   "Kubernetes Operators",
+			Content: "<p>Writing controllers in Go</p>",
+		},
+		{
+			Title:   "Deploying Services",
+			Content: "<p>We deploy everything on kubernetes clusters</p>",
+		},
+		{
+			Title:   "Cooking Pasta",
+			Content: "<p>Nothing about containers here</p>",
+		},
+	}
+
+	for _, articleReq := range articles {
+		body, _ := json.Marshal(articleReq)
+		req := httptest.NewRequest("POST", "/api/v1/articles", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+suite.token)
+
+		w := httptest.NewRecorder()
+		suite.router.ServeHTTP(w, req)
+
+		var createResp struct {
+			Data models.Article `json:"data"`
+		}
+		err := json.Unmarshal(w.Body.Bytes(), &createResp)
+		suite.NoError(err)
+
+		suite.Equal(http.StatusOK, suite.updateVersionStatus(suite.token, createResp.Data.ID, createResp.Data.LatestVersionID, models.StatusPublished, ""))
+	}
+
+	req := httptest.NewRequest("GET", "/api/v1/public/articles?q=kubernetes", nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusOK, w.Code)
+
+	var searchResp struct {
+		Data struct {
+			Articles []models.Article `json:"articles"`
+			Total    int64            `json:"total"`
+		} `json:"data"`
+	}
+	err := json.Unmarshal(w.Body.Bytes(), &searchResp)
+	suite.NoError(err)
+
+	// Kecocokan di judul harus berada di atas kecocokan di konten
+	suite.Equal(int64(2), searchResp.Data.Total)
+	suite.Require().Len(searchResp.Data.Articles, 2)
+	suite.Equal("Kubernetes Operators", searchResp.Data.Articles[0].Title)
+	suite.Equal("Deploying Services", searchResp.Data.Articles[1].Title)
+	suite.Greater(searchResp.Data.Articles[0].SearchRank, searchResp.Data.Articles[1].SearchRank)
+	suite.Contains(searchResp.Data.Articles[1].Snippet, "<mark>kubernetes</mark>")
+	suite.NotContains(searchResp.Data.Articles[1].Snippet, "<p>")
+}
+
+func (suite *IntegrationTestSuite) TestArticleSlugs() {
+	var created []models.Article
+	for i := 0; i < 2; i++ {
+		body, _ := json.Marshal(models.CreateArticleRequest{
+			Title:   "Crème Brûlée",
+			Content: "Dessert",
+		})
+		req := httptest.NewRequest("POST", "/api/v1/articles", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+suite.token)
+
+		w := httptest.NewRecorder()
+		suite.router.ServeHTTP(w, req)
+		suite.Equal(http.StatusOK, w.Code)
+
+		var createResp struct {
+			Data models.Article `json:"data"`
+		}
+		err := json.Unmarshal(w.Body.Bytes(), &createResp)
+		suite.NoError(err)
+		created = append(created, createResp.Data)
+	}
+
+	// Judul yang sama mendapat suffix collision
+	suite.Equal("creme-brulee", created[0].Slug)
+	suite.Equal("creme-brulee-2", created[1].Slug)
+
+	article := created[0]
+	suite.Equal(http.StatusOK, suite.updateVersionStatus(suite.token, article.ID, article.LatestVersionID, models.StatusPublished, ""))
+
+	req := httptest.NewRequest("GET", "/api/v1/public/articles/by-slug/creme-brulee", nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusOK, w.Code)
+
+	// Artikel yang belum dipublikasikan tidak bisa diakses lewat slug
+	req = httptest.NewRequest("GET", "/api/v1/public/articles/by-slug/creme-brulee-2", nil)
+	w = httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusNotFound, w.Code)
+
+	// Publikasikan versi dengan judul baru
+	body, _ := json.Marshal(models.CreateArticleVersionRequest{
+		Title:         "Tiramisu Recipe",
+		Content:       "Dessert",
+		BaseVersionID: &article.LatestVersionID,
+	})
+	req = httptest.NewRequest("POST", fmt.Sprintf("/api/v1/articles/%d/versions", article.ID), bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+suite.token)
+	w = httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusOK, w.Code)
+
+	var versionResp struct {
+		Data models.ArticleVersion `json:"data"`
+	}
+	err := json.Unmarshal(w.Body.Bytes(), &versionResp)
+	suite.NoError(err)
+	suite.Equal(http.StatusOK, suite.updateVersionStatus(suite.token, article.ID, versionResp.Data.ID, models.StatusPublished, ""))
+
+	// Slug lama di-redirect permanen ke slug baru
+	req = httptest.NewRequest("GET", "/api/v1/public/articles/by-slug/creme-brulee", nil)
+	w = httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusMovedPermanently, w.Code)
+	suite.Equal("/api/v1/public/articles/by-slug/tiramisu-recipe", w.Header().Get("Location"))
+
+	req = httptest.NewRequest("GET", "/api/v1/public/articles/by-slug/tiramisu-recipe", nil)
+	w = httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusOK, w.Code)
+
+	var getResp struct {
+		Data models.Article `json:"data"`
+	}
+	err = json.Unmarshal(w.Body.Bytes(), &getResp)
+	suite.NoError(err)
+	suite.Equal(article.ID, getResp.Data.ID)
+
+	// Slug lama tetap milik artikel ini, artikel baru tidak boleh memakainya
+	var newSlug string
+	suite.db.Raw("SELECT slug FROM articles WHERE id = ?", created[1].ID).Scan(&newSlug)
+	suite.Equal("creme-brulee-2", newSlug)
+}
+
+func (suite *IntegrationTestSuite) TestSlugify() {
+	cases := map[string]string{
+		"Crème Brûlée":       "creme-brulee",
+		"Don't Stop":         "dont-stop",
+		"Straße":             "strasse",
+		"Йогурт":             "yogurt",
+		"Київ":               "kiyiv",
+		"Ελληνικά":           "ellinika",
+		"ガイド":                "ガイド",
+		"ガイドブック 入門":          "ガイドブック-入門",
+		"ｶﾞｲﾄﾞ ﾊﾟｽ":          "ｶﾞｲﾄﾞ-ﾊﾟｽ",
+		"हिन्दी लेख":         "हिन्दी-लेख",
+		"  Hello,   World! ": "hello-world",
+	}
+	for title, expected := range cases {
+		suite.Equal(expected, helper.Slugify(title), title)
+	}
+}
+
+func (suite *IntegrationTestSuite) TestPublicFeeds() {
+	titles := []string{"First Feed Article", "Second Feed Article"}
+	for i, title := range titles {
+		body, _ := json.Marshal(models.CreateArticleRequest{
+			Title:   title,
+			Content: "<p>Feed content</p>",
+			Tags:    []string{fmt.Sprintf("feed-%d", i)},
+		})
+		req := httptest.NewRequest("POST", "/api/v1/articles", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+suite.token)
+
+		w := httptest.NewRecorder()
+		suite.router.ServeHTTP(w, req)
+
+		var createResp struct {
+			Data models.Article `json:"data"`
+		}
+		err := json.Unmarshal(w.Body.Bytes(), &createResp)
+		suite.NoError(err)
+		suite.Equal(http.StatusOK, suite.updateVersionStatus(suite.token, createResp.Data.ID, createResp.Data.LatestVersionID, models.StatusPublished, ""))
+	}
+
+	req := httptest.NewRequest("GET", "/api/v1/public/feed.rss", nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Contains(w.Header().Get("Content-Type"), "application/rss+xml")
+	suite.Contains(w.Body.String(), "<title>First Feed Article</title>")
+	suite.Contains(w.Body.String(), "<title>Second Feed Article</title>")
+
+	etag := w.Header().Get("ETag")
+	lastModified := w.Header().Get("Last-Modified")
+	suite.NotEmpty(etag)
+	suite.NotEmpty(lastModified)
+
+	// Conditional GET
+	req = httptest.NewRequest("GET", "/api/v1/public/feed.rss", nil)
+	req.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusNotModified, w.Code)
+
+	req = httptest.NewRequest("GET", "/api/v1/public/feed.rss", nil)
+	req.Header.Set("If-Modified-Since", lastModified)
+	w = httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusNotModified, w.Code)
+
+	// Filter tag dan limit
+	var tag models.Tag
+	suite.db.Where("name = ?", "feed-0").First(&tag)
+	req = httptest.NewRequest("GET", fmt.Sprintf("/api/v1/public/feed.atom?tag_id=%d", tag.ID), nil)
+	w = httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Contains(w.Header().Get("Content-Type"), "application/atom+xml")
+	suite.Contains(w.Body.String(), "<title>First Feed Article</title>")
+	suite.NotContains(w.Body.String(), "<title>Second Feed Article</title>")
+
+	req = httptest.NewRequest("GET", "/api/v1/public/feed.atom?limit=1", nil)
+	w = httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Equal(1, strings.Count(w.Body.String(), "<entry>"))
+}
+
+func (suite *IntegrationTestSuite) TestSitemapCacheExpires() {
+	// Service terpisah tidak menerima PublicationChanged dari router suite,
+	// seperti replica lain; perubahan hanya terbawa setelah ttl lewat
+	sitemapService := services.NewSitemapService(
+		repositories.NewArticleRepository(suite.db),
+		repositories.NewTagRepository(suite.db),
+		services.SitemapMaxURLs,
+		50*time.Millisecond,
+	)
+	sitemap, err := sitemapService.GetSitemap()
+	suite.Require().NoError(err)
+	suite.Empty(sitemap.Pages)
+
+	body, _ := json.Marshal(models.CreateArticleRequest{Title: "Replica Article", Content: "Content"})
+	req := httptest.NewRequest("POST", "/api/v1/articles", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+suite.token)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.Require().Equal(http.StatusOK, w.Code)
+
+	var createResp struct {
+		Data models.Article `json:"data"`
+	}
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &createResp))
+	suite.Equal(http.StatusOK, suite.updateVersionStatus(suite.token, createResp.Data.ID, createResp.Data.LatestVersionID, models.StatusPublished, ""))
+
+	sitemap, err = sitemapService.GetSitemap()
+	suite.Require().NoError(err)
+	suite.Empty(sitemap.Pages)
+
+	time.Sleep(100 * time.Millisecond)
+	sitemap, err = sitemapService.GetSitemap()
+	suite.Require().NoError(err)
+	suite.Require().Len(sitemap.Pages, 1)
+	suite.Equal("/api/v1/public/articles/by-slug/replica-article", sitemap.Pages[0][0].Path)
+}
+
+func (suite *IntegrationTestSuite) TestSitemap() {
+	var articles []models.Article
+	for i := 1; i <= 3; i++ {
+		body, _ := json.Marshal(models.CreateArticleRequest{
+			Title:   fmt.Sprintf("Sitemap Article %d", i),
+			Content: "Content",
+			Tags:    []string{"sitemap"},
+		})
+		req := httptest.NewRequest("POST", "/api/v1/articles", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+suite.token)
+
+		w := httptest.NewRecorder()
+		suite.router.ServeHTTP(w, req)
+
+		var createResp struct {
+			Data models.Article `json:"data"`
+		}
+		err := json.Unmarshal(w.Body.Bytes(), &createResp)
+		suite.NoError(err)
+		suite.Equal(http.StatusOK, suite.updateVersionStatus(suite.token, createResp.Data.ID, createResp.Data.LatestVersionID, models.StatusPublished, ""))
+		articles = append(articles, createResp.Data)
+	}
+
+	req := httptest.NewRequest("GET", "/sitemap.xml", nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Contains(w.Body.String(), "<urlset")
+	suite.Contains(w.Body.String(), "/api/v1/public/articles/by-slug/sitemap-article-1</loc>")
+	suite.Contains(w.Body.String(), "/api/v1/public/articles/by-slug/sitemap-article-3</loc>")
+	suite.Contains(w.Body.String(), "/api/v1/public/articles?tag_id=")
+	suite.Contains(w.Body.String(), "<lastmod>")
+
+	// Unpublish membuat sitemap dibangun ulang
+	suite.Equal(http.StatusOK, suite.updateVersionStatus(suite.token, articles[2].ID, articles[2].LatestVersionID, models.StatusArchivedVersion, ""))
+
+	req = httptest.NewRequest("GET", "/sitemap.xml", nil)
+	w = httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.NotContains(w.Body.String(), "sitemap-article-3</loc>")
+
+	// Lebih dari satu halaman menghasilkan sitemap index
+	sitemapHandler := handlers.NewSitemapHandler(services.NewSitemapService(
+		repositories.NewArticleRepository(suite.db),
+		repositories.NewTagRepository(suite.db),
+		2,
+		0,
+	))
+	router := gin.New()
+	router.GET("/sitemap.xml", sitemapHandler.GetSitemap)
+	router.GET("/sitemaps/:name", sitemapHandler.GetSitemapPage)
+
+	req = httptest.NewRequest("GET", "/sitemap.xml", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Contains(w.Body.String(), "<sitemapindex")
+	suite.Contains(w.Body.String(), "/sitemaps/sitemap-1.xml</loc>")
+	suite.Contains(w.Body.String(), "/sitemaps/sitemap-2.xml</loc>")
+
+	req = httptest.NewRequest("GET", "/sitemaps/sitemap-2.xml", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Equal(1, strings.Count(w.Body.String(), "<url>"))
+
+	req = httptest.NewRequest("GET", "/sitemaps/sitemap-3.xml", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusNotFound, w.Code)
+}
+
+func (suite *IntegrationTestSuite) TestMarkdownContentRendering() {
+	body, _ := json.Marshal(models.CreateArticleRequest{
+		Title:         "Markdown Article",
+		Content:       "# Heading\n\n**bold** text <script>alert(1)</script> [link](javascript:alert(1))",
+		ContentFormat: models.ContentFormatMarkdown,
+	})
+	req := httptest.NewRequest("POST", "/api/v1/articles", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+suite.token)
+
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusOK, w.Code)
+
+	var createResp struct {
+		Data models.Article `json:"data"`
+	}
+	err := json.Unmarshal(w.Body.Bytes(), &createResp)
+	suite.NoError(err)
+	suite.Equal(models.ContentFormatMarkdown, createResp.Data.LatestVersion.ContentFormat)
+
+	article := createResp.Data
+	suite.Equal(http.StatusOK, suite.updateVersionStatus(suite.token, article.ID, article.LatestVersionID, models.StatusPublished, ""))
+
+	req = httptest.NewRequest("GET", fmt.Sprintf("/api/v1/public/articles/%d", article.ID), nil)
+	w = httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusOK, w.Code)
+
+	var getResp struct {
+		Data models.Article `json:"data"`
+	}
+	err = json.Unmarshal(w.Body.Bytes(), &getResp)
+	suite.NoError(err)
+
+	published := getResp.Data.PublishedVersion
+	suite.Require().NotNil(published)
+	suite.Contains(published.Content, "**bold**")
+	suite.Contains(published.ContentHTML, "<h1>Heading</h1>")
+	suite.Contains(published.ContentHTML, "<strong>bold</strong>")
+	suite.NotContains(published.ContentHTML, "<script")
+	suite.NotContains(published.ContentHTML, "javascript:")
+
+	// Format yang tidak dikenal ditolak
+	body, _ = json.Marshal(map[string]interface{}{
+		"title":          "Invalid Format",
+		"content":        "Content",
+		"content_format": "rtf",
+	})
+	req = httptest.NewRequest("POST", "/api/v1/articles", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+suite.token)
+
+	w = httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusBadRequest, w.Code)
+}
+
+func (suite *IntegrationTestSuite) TestConcurrentVersionCreation() {
+	body, _ := json.Marshal(models.CreateArticleRequest{
+		Title:   "Concurrent Article",
+		Content: "Initial content",
+		Tags:    []string{"concurrency"},
+	})
+	req := httptest.NewRequest("POST", "/api/v1/articles", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+suite.token)
+
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusOK, w.Code)
+
+	var createResp struct {
+		Data models.Article `json:"data"`
+	}
+	err := json.Unmarshal(w.Body.Bytes(), &createResp)
+	suite.NoError(err)
+	articleID := createResp.Data.ID
+
+	const workers = 20
+	var wg sync.WaitGroup
+	codes := make(chan int, workers)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			body, _ := json.Marshal(models.CreateArticleVersionRequest{
+				Title:   fmt.Sprintf("Concurrent Version %d", i),
+				Content: "Concurrent content",
+				Tags:    []string{"concurrency", fmt.Sprintf("worker-%d", i%3)},
+			})
+			req := httptest.NewRequest("POST", fmt.Sprintf("/api/v1/articles/%d/versions", articleID), bytes.NewBuffer(body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer "+suite.token)
+			// Precondition sengaja dilewati, yang diuji adalah alokasi nomor versi
+			req.Header.Set("If-Match", "*")
+
+			w := httptest.NewRecorder()
+			suite.router.ServeHTTP(w, req)
+			codes <- w.Code
+		}(i)
+	}
+	wg.Wait()
+	close(codes)
+
+	for code := range codes {
+		suite.Equal(http.StatusOK, code)
+	}
+
+	// Nomor versi harus unik dan berurutan tanpa celah
+	var numbers []int
+	suite.db.Raw("SELECT version_number FROM article_versions WHERE article_id = ? ORDER BY version_number", articleID).Scan(&numbers)
+	suite.Require().Len(numbers, workers+1)
+	for i, n := range numbers {
+		suite.Equal(i+1, n)
+	}
+
+	// latest_version_id menunjuk ke versi dengan nomor tertinggi
+	var latestNumber int
+	suite.db.Raw(`SELECT av.version_number FROM articles a
+		JOIN article_versions av ON av.id = a.latest_version_id
+		WHERE a.id = ?`, articleID).Scan(&latestNumber)
+	suite.Equal(workers+1, latestNumber)
+
+	// Tag baru yang dibuat bersamaan tidak terduplikasi
+	var tagCount int64
+	suite.db.Model(&models.Tag{}).Where("name LIKE ?", "worker-%").Count(&tagCount)
+	suite.Equal(int64(3), tagCount)
+}
+
+func (suite *IntegrationTestSuite) TestOptimisticConcurrency() {
+	body, _ := json.Marshal(models.CreateArticleRequest{
+		Title:   "Contested Article",
+		Content: "<p>Base</p>",
+	})
+	req := httptest.NewRequest("POST", "/api/v1/articles", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+suite.token)
+
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	var createResp struct {
+		Data models.Article `json:"data"`
+	}
+	err := json.Unmarshal(w.Body.Bytes(), &createResp)
+	suite.NoError(err)
+	article := createResp.Data
+
+	etag := suite.articleETag(suite.token, article.ID)
+	suite.NotEmpty(etag)
+
+	createVersion := func(payload models.CreateArticleVersionRequest, ifMatch string) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(payload)
+		req := httptest.NewRequest("POST", fmt.Sprintf("/api/v1/articles/%d/versions", article.ID), bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+suite.token)
+		if ifMatch != "" {
+			req.Header.Set("If-Match", ifMatch)
+		}
+
+		w := httptest.NewRecorder()
+		suite.router.ServeHTTP(w, req)
+		return w
+	}
+
+	// Tanpa If-Match maupun base_version_id
+	w = createVersion(models.CreateArticleVersionRequest{Title: "No Precondition", Content: "x"}, "")
+	suite.Equal(http.StatusPreconditionRequired, w.Code)
+
+	// Editor pertama berhasil
+	w = createVersion(models.CreateArticleVersionRequest{Title: "Editor A", Content: "a"}, etag)
+	suite.Equal(http.StatusOK, w.Code)
+
+	var versionResp struct {
+		Data models.ArticleVersion `json:"data"`
+	}
+	err = json.Unmarshal(w.Body.Bytes(), &versionResp)
+	suite.NoError(err)
+	versionA := versionResp.Data
+
+	// Editor kedua memakai ETag yang sama dan ditolak beserta latest version terbaru
+	w = createVersion(models.CreateArticleVersionRequest{Title: "Editor B", Content: "b"}, etag)
+	suite.Equal(http.StatusPreconditionFailed, w.Code)
+	suite.NotEqual(etag, w.Header().Get("ETag"))
+
+	var staleResp struct {
+		Data struct {
+			LatestVersion models.ArticleVersion `json:"latest_version"`
+		} `json:"data"`
+	}
+	err = json.Unmarshal(w.Body.Bytes(), &staleResp)
+	suite.NoError(err)
+	suite.Equal(versionA.ID, staleResp.Data.LatestVersion.ID)
+	suite.Equal("Editor A", staleResp.Data.LatestVersion.Title)
+
+	// base_version_id lama juga ditolak, yang terbaru diterima
+	w = createVersion(models.CreateArticleVersionRequest{Title: "Editor B", Content: "b", BaseVersionID: &article.LatestVersionID}, "")
+	suite.Equal(http.StatusPreconditionFailed, w.Code)
+
+	w = createVersion(models.CreateArticleVersionRequest{Title: "Editor B", Content: "b", BaseVersionID: &versionA.ID}, "")
+	suite.Equal(http.StatusOK, w.Code)
+
+	// Perubahan status dengan ETag usang ditolak
+	body, _ = json.Marshal(models.UpdateVersionStatusRequest{Status: models.StatusPublished})
+	req = httptest.NewRequest("PUT", fmt.Sprintf("/api/v1/articles/%d/versions/%d/status", article.ID, versionA.ID), bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+suite.token)
+	req.Header.Set("If-Match", etag)
+
+	w = httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusPreconditionFailed, w.Code)
+}
+
+func (suite *IntegrationTestSuite) TestUpdateArticleMetadata() {
+	writerToken, writerID := suite.registerUser("patchwriter", "patchwriter@example.com", models.RoleWriter)
+	_, otherWriterID := suite.registerUser("patchwriter2", "patchwriter2@example.com", models.RoleWriter)
+
+	body, _ := json.Marshal(models.CreateArticleRequest{
+		Title:   "Original Title",
+		Content: "<p>Body</p>",
+	})
+	req := httptest.NewRequest("POST", "/api/v1/articles", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+writerToken)
+
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	var createResp struct {
+		Data models.Article `json:"data"`
+	}
+	err := json.Unmarshal(w.Body.Bytes(), &createResp)
+	suite.NoError(err)
+	article := createResp.Data
+
+	patch := func(token string, payload map[string]interface{}, ifMatch string) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(payload)
+		req := httptest.NewRequest("PATCH", fmt.Sprintf("/api/v1/articles/%d", article.ID), bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		if ifMatch != "" {
+			req.Header.Set("If-Match", ifMatch)
+		}
+
+		w := httptest.NewRecorder()
+		suite.router.ServeHTTP(w, req)
+		return w
+	}
+
+	// Writer boleh mengubah judul artikelnya sendiri
+	w = patch(writerToken, map[string]interface{}{"title": "Renamed Title"}, "")
+	suite.Equal(http.StatusOK, w.Code)
+
+	var patchResp struct {
+		Data models.Article `json:"data"`
+	}
+	err = json.Unmarshal(w.Body.Bytes(), &patchResp)
+	suite.NoError(err)
+	suite.Equal("Renamed Title", patchResp.Data.Title)
+	suite.Equal("renamed-title", patchResp.Data.Slug)
+	suite.NotEmpty(w.Header().Get("ETag"))
+
+	// Slug lama masuk riwayat
+	var slugHistory int64
+	suite.db.Raw("SELECT COUNT(*) FROM article_slugs WHERE article_id = ? AND slug = ?", article.ID, "original-title").Scan(&slugHistory)
+	suite.Equal(int64(1), slugHistory)
+
+	// Tetapi tidak boleh memindahkan author atau menandai featured
+	w = patch(writerToken, map[string]interface{}{"author_id": otherWriterID}, "")
+	suite.Equal(http.StatusBadRequest, w.Code)
+	w = patch(writerToken, map[string]interface{}{"featured": true}, "")
+	suite.Equal(http.StatusBadRequest, w.Code)
+
+	// If-Match usang ditolak
+	w = patch(suite.token, map[string]interface{}{"featured": true}, `"0-0"`)
+	suite.Equal(http.StatusPreconditionFailed, w.Code)
+
+	// Admin memindahkan artikel dan menandai featured
+	w = patch(suite.token, map[string]interface{}{"author_id": otherWriterID, "featured": true}, "")
+	suite.Equal(http.StatusOK, w.Code)
+	err = json.Unmarshal(w.Body.Bytes(), &patchResp)
+	suite.NoError(err)
+	suite.Equal(otherWriterID, patchResp.Data.AuthorID)
+	suite.True(patchResp.Data.Featured)
+
+	// Author yang tidak ada ditolak
+	w = patch(suite.token, map[string]interface{}{"author_id": 999999}, "")
+	suite.Equal(http.StatusBadRequest, w.Code)
+
+	// Publish versi baru membuat judul artikel mengikuti judul versi
+	body, _ = json.Marshal(models.CreateArticleVersionRequest{
+		Title:         "Published Title",
+		Content:       "<p>Body v2</p>",
+		BaseVersionID: &article.LatestVersionID,
+	})
+	req = httptest.NewRequest("POST", fmt.Sprintf("/api/v1/articles/%d/versions", article.ID), bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+suite.token)
+
+	w = httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusOK, w.Code)
+
+	var versionResp struct {
+		Data models.ArticleVersion `json:"data"`
+	}
+	err = json.Unmarshal(w.Body.Bytes(), &versionResp)
+	suite.NoError(err)
+
+	suite.Equal(http.StatusOK, suite.updateVersionStatus(suite.token, article.ID, versionResp.Data.ID, models.StatusPublished, ""))
+
+	req = httptest.NewRequest("GET", fmt.Sprintf("/api/v1/articles/%d", article.ID), nil)
+	req.Header.Set("Authorization", "Bearer "+suite.token)
+
+	w = httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	var getResp struct {
+		Data models.Article `json:"data"`
+	}
+	err = json.Unmarshal(w.Body.Bytes(), &getResp)
+	suite.NoError(err)
+	suite.Equal("Published Title", getResp.Data.Title)
+	suite.Equal("published-title", getResp.Data.Slug)
+
+	// Audit mencatat semua perubahan beserta pelakunya, terbaru lebih dulu
+	req = httptest.NewRequest("GET", fmt.Sprintf("/api/v1/articles/%d/audits", article.ID), nil)
+	req.Header.Set("Authorization", "Bearer "+suite.token)
+
+	w = httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusOK, w.Code)
+
+	var auditResp struct {
+		Data []models.ArticleAudit `json:"data"`
+	}
+	err = json.Unmarshal(w.Body.Bytes(), &auditResp)
+	suite.NoError(err)
+	suite.Len(auditResp.Data, 4)
+
+	publishAudit := auditResp.Data[0]
+	suite.Equal("title", publishAudit.Field)
+	suite.Equal("Renamed Title", publishAudit.OldValue)
+	suite.Equal("Published Title", publishAudit.NewValue)
+	suite.Equal(models.AuditSourcePublish, publishAudit.Source)
+
+	fields := map[string]models.ArticleAudit{}
+	for _, audit := range auditResp.Data[1:] {
+		suite.Equal(models.AuditSourcePatch, audit.Source)
+		fields[audit.Field] = audit
+	}
+	suite.Equal(writerID, *fields["title"].ActorID)
+	suite.Equal(suite.userID, *fields["author_id"].ActorID)
+	suite.Equal(fmt.Sprint(otherWriterID), fields["author_id"].NewValue)
+	suite.Equal("true", fields["featured"].NewValue)
+}
+
+func (suite *IntegrationTestSuite) TestArticleTrash() {
+	editorToken, _ := suite.registerUser("trasheditor", "trasheditor@example.com", models.RoleEditor)
+
+	createArticle := func(title, tag string) models.Article {
+		body, _ := json.Marshal(models.CreateArticleRequest{
+			Title:   title,
+			Content: "<p>Trash me</p>",
+			Tags:    []string{tag},
+		})
+		req := httptest.NewRequest("POST", "/api/v1/articles", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+suite.token)
+
+		w := httptest.NewRecorder()
+		suite.router.ServeHTTP(w, req)
+
+		var createResp struct {
+			Data models.Article `json:"data"`
+		}
+		err := json.Unmarshal(w.Body.Bytes(), &createResp)
+		suite.NoError(err)
+
+		article := createResp.Data
+		suite.Equal(http.StatusOK, suite.updateVersionStatus(suite.token, article.ID, article.LatestVersionID, models.StatusPublished, ""))
+		return article
+	}
+	do := func(method, url, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, url, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		suite.router.ServeHTTP(w, req)
+		return w
+	}
+	usageCount := func(tag string) int {
+		// usage_count dihitung ulang oleh job background, picu manual di test
+		suite.Require().Equal(http.StatusOK, do("POST", "/api/v1/admin/tags/recompute-trending", suite.token).Code)
+
+		var t models.Tag
+		suite.NoError(suite.db.Where("name = ?", tag).First(&t).Error)
+		return t.UsageCount
+	}
+
+	article := createArticle("Trash Article", "trash-tag")
+	suite.Equal(1, usageCount("trash-tag"))
+
+	w := do("DELETE", fmt.Sprintf("/api/v1/articles/%d", article.ID), suite.token)
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Equal(0, usageCount("trash-tag"))
+
+	// Hanya admin yang boleh mengakses trash
+	w = do("GET", "/api/v1/admin/trash/articles", editorToken)
+	suite.Equal(http.StatusBadRequest, w.Code)
+	w = do("POST", fmt.Sprintf("/api/v1/admin/trash/articles/%d/restore", article.ID), editorToken)
+	suite.Equal(http.StatusBadRequest, w.Code)
+
+	w = do("GET", "/api/v1/admin/trash/articles", suite.token)
+	suite.Equal(http.StatusOK, w.Code)
+
+	var trashResp struct {
+		Data struct {
+			Articles []models.TrashedArticle `json:"articles"`
+			Total    int64                   `json:"total"`
+		} `json:"data"`
+	}
+	err := json.Unmarshal(w.Body.Bytes(), &trashResp)
+	suite.NoError(err)
+	suite.Equal(int64(1), trashResp.Data.Total)
+	suite.Equal(article.ID, trashResp.Data.Articles[0].ID)
+	suite.False(trashResp.Data.Articles[0].DeletedAt.IsZero())
+
+	// Restore mengembalikan artikel, versi dan usage count tag
+	w = do("POST", fmt.Sprintf("/api/v1/admin/trash/articles/%d/restore", article.ID), suite.token)
+	suite.Equal(http.StatusOK, w.Code)
+	w = do("GET", fmt.Sprintf("/api/v1/public/articles/by-slug/%s", article.Slug), "")
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Equal(1, usageCount("trash-tag"))
+
+	// Artikel yang tidak ada di trash tidak bisa di-restore atau di-purge
+	w = do("POST", fmt.Sprintf("/api/v1/admin/trash/articles/%d/restore", article.ID), suite.token)
+	suite.Equal(http.StatusBadRequest, w.Code)
+	w = do("DELETE", fmt.Sprintf("/api/v1/admin/trash/articles/%d/purge", article.ID), suite.token)
+	suite.Equal(http.StatusBadRequest, w.Code)
+
+	// Purge menghapus permanen artikel, versi dan relasi tag
+	w = do("DELETE", fmt.Sprintf("/api/v1/articles/%d", article.ID), suite.token)
+	suite.Equal(http.StatusOK, w.Code)
+	w = do("DELETE", fmt.Sprintf("/api/v1/admin/trash/articles/%d/purge", article.ID), suite.token)
+	suite.Equal(http.StatusOK, w.Code)
+
+	var count int64
+	suite.db.Unscoped().Model(&models.Article{}).Where("id = ?", article.ID).Count(&count)
+	suite.Equal(int64(0), count)
+	suite.db.Unscoped().Model(&models.ArticleVersion{}).Where("article_id = ?", article.ID).Count(&count)
+	suite.Equal(int64(0), count)
+	suite.db.Model(&models.ArticleVersionTag{}).Where("article_version_id = ?", article.LatestVersionID).Count(&count)
+	suite.Equal(int64(0), count)
+	suite.Equal(0, usageCount("trash-tag"))
+
+	// Job retensi hanya menghapus artikel yang lebih lama dari cutoff
+	expired := createArticle("Expired Trash", "expired-tag")
+	kept := createArticle("Kept Trash", "kept-tag")
+	do("DELETE", fmt.Sprintf("/api/v1/articles/%d", expired.ID), suite.token)
+	do("DELETE", fmt.Sprintf("/api/v1/articles/%d", kept.ID), suite.token)
+	suite.db.Exec("UPDATE articles SET deleted_at = ? WHERE id = ?", time.Now().UTC().Add(-48*time.Hour), expired.ID)
+
+	err = suite.articleService.PurgeTrashedBefore(time.Now().Add(-24 * time.Hour))
+	suite.NoError(err)
+
+	suite.db.Unscoped().Model(&models.Article{}).Where("id = ?", expired.ID).Count(&count)
+	suite.Equal(int64(0), count)
+	suite.db.Unscoped().Model(&models.Article{}).Where("id = ?", kept.ID).Count(&count)
+	suite.Equal(int64(1), count)
+}
+
+func (suite *IntegrationTestSuite) TestTagRenameMergeAlias() {
+	writerToken, _ := suite.registerUser("tagwriter", "tagwriter@example.com", models.RoleWriter)
+
+	createPublished := func(title string, tags []string) models.Article {
+		body, _ := json.Marshal(models.CreateArticleRequest{
+			Title:   title,
+			Content: "<p>Tag management</p>",
+			Tags:    tags,
+		})
+		req := httptest.NewRequest("POST", "/api/v1/articles", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+suite.token)
+
+		w := httptest.NewRecorder()
+		suite.router.ServeHTTP(w, req)
+		suite.Equal(http.StatusOK, w.Code)
+
+		var createResp struct {
+			Data models.Article `json:"data"`
+		}
+		err := json.Unmarshal(w.Body.Bytes(), &createResp)
+		suite.NoError(err)
+
+		article := createResp.Data
+		suite.Equal(http.StatusOK, suite.updateVersionStatus(suite.token, article.ID, article.LatestVersionID, models.StatusPublished, ""))
+		return article
+	}
+	send := func(method, url, token string, payload interface{}) *httptest.ResponseRecorder {
+		var body []byte
+		if payload != nil {
+			body, _ = json.Marshal(payload)
+		}
+		req := httptest.NewRequest(method, url, bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		suite.router.ServeHTTP(w, req)
+		return w
+	}
+	tagByName := func(name string) models.Tag {
+		var tag models.Tag
+		suite.NoError(suite.db.Where("name = ?", name).First(&tag).Error)
+		return tag
+	}
+	versionTagNames := func(versionID uint) []string {
+		var names []string
+		suite.db.Table("article_version_tags avt").
+			Joins("JOIN tags t ON t.id = avt.tag_id").
+			Where("avt.article_version_id = ?", versionID).
+			Order("t.name").
+			Pluck("t.name", &names)
+		return names
+	}
+
+	createPublished("Golang Tips", []string{"golang", "backend"})
+	createPublished("Go Tips", []string{"go", "backend"})
+	both := createPublished("Go and Golang", []string{"golang", "go"})
+
+	golang := tagByName("golang")
+	goTag := tagByName("go")
+	backend := tagByName("backend")
+
+	// Hanya admin yang boleh mengelola tag
+	w := send("PUT", fmt.Sprintf("/api/v1/tags/%d", backend.ID), writerToken, models.RenameTagRequest{Name: "server"})
+	suite.Equal(http.StatusUnauthorized, w.Code)
+
+	// Rename ke nama tag lain ditolak, rename biasa menyimpan nama lama sebagai alias
+	w = send("PUT", fmt.Sprintf("/api/v1/tags/%d", backend.ID), suite.token, models.RenameTagRequest{Name: "go"})
+	suite.Equal(http.StatusBadRequest, w.Code)
+
+	w = send("PUT", fmt.Sprintf("/api/v1/tags/%d", backend.ID), suite.token, models.RenameTagRequest{Name: "server"})
+	suite.Equal(http.StatusOK, w.Code)
+
+	var tagResp struct {
+		Data models.Tag `json:"data"`
+	}
+	err := json.Unmarshal(w.Body.Bytes(), &tagResp)
+	suite.NoError(err)
+	suite.Equal("server", tagResp.Data.Name)
+	suite.Require().Len(tagResp.Data.Aliases, 1)
+	suite.Equal("backend", tagResp.Data.Aliases[0].Alias)
+
+	// Merge golang ke go, termasuk versi yang memiliki keduanya
+	w = send("POST", fmt.Sprintf("/api/v1/tags/%d/merge", golang.ID), suite.token, models.MergeTagRequest{TargetID: goTag.ID})
+	suite.Equal(http.StatusOK, w.Code)
+	err = json.Unmarshal(w.Body.Bytes(), &tagResp)
+	suite.NoError(err)
+	w = send("POST", "/api/v1/admin/tags/recompute-trending", suite.token, nil)
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Equal(3, tagByName("go").UsageCount)
+	suite.Equal([]string{"go"}, versionTagNames(both.LatestVersionID))
+
+	var count int64
+	suite.db.Unscoped().Model(&models.Tag{}).Where("id = ?", golang.ID).Count(&count)
+	suite.Equal(int64(0), count)
+
+	// Nama lama (alias) otomatis diarahkan ke tag kanonik
+	aliased := createPublished("Aliased Tags", []string{"golang", "backend", "go"})
+	suite.Equal([]string{"go", "server"}, versionTagNames(aliased.LatestVersionID))
+
+	// Alias tidak boleh sama dengan nama tag atau alias tag lain
+	w = send("POST", fmt.Sprintf("/api/v1/tags/%d/aliases", goTag.ID), suite.token, models.CreateTagAliasRequest{Alias: "server"})
+	suite.Equal(http.StatusBadRequest, w.Code)
+	w = send("POST", fmt.Sprintf("/api/v1/tags/%d/aliases", backend.ID), suite.token, models.CreateTagAliasRequest{Alias: "golang"})
+	suite.Equal(http.StatusBadRequest, w.Code)
+
+	w = send("POST", fmt.Sprintf("/api/v1/tags/%d/aliases", goTag.ID), suite.token, models.CreateTagAliasRequest{Alias: "gopher"})
+	suite.Equal(http.StatusOK, w.Code)
+	err = json.Unmarshal(w.Body.Bytes(), &tagResp)
+	suite.NoError(err)
+	suite.Len(tagResp.Data.Aliases, 2)
+
+	var gopher models.TagAlias
+	suite.NoError(suite.db.Where("alias = ?", "gopher").First(&gopher).Error)
+	w = send("DELETE", fmt.Sprintf("/api/v1/tags/%d/aliases/%d", goTag.ID, gopher.ID), suite.token, nil)
+	suite.Equal(http.StatusOK, w.Code)
+	w = send("DELETE", fmt.Sprintf("/api/v1/tags/%d/aliases/%d", goTag.ID, gopher.ID), suite.token, nil)
+	suite.Equal(http.StatusBadRequest, w.Code)
+
+	// Tag yang dihapus hilang dari daftar, dan dipulihkan jika dipakai lagi
+	w = send("DELETE", fmt.Sprintf("/api/v1/tags/%d", backend.ID), suite.token, nil)
+	suite.Equal(http.StatusOK, w.Code)
+	w = send("GET", fmt.Sprintf("/api/v1/tags/%d", backend.ID), suite.token, nil)
+	suite.NotEqual(http.StatusOK, w.Code)
+
+	createPublished("Server Again", []string{"server"})
+	suite.Equal(backend.ID, tagByName("server").ID)
+}
+
+func (suite *IntegrationTestSuite) TestTagNameNormalization() {
+	createArticle := func(tags []string) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(models.CreateArticleRequest{
+			Title:   "Normalized Tags",
+			Content: "<p>Tags</p>",
+			Tags:    tags,
+		})
+		req := httptest.NewRequest("POST", "/api/v1/articles", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+suite.token)
+
+		w := httptest.NewRecorder()
+		suite.router.ServeHTTP(w, req)
+		return w
+	}
+
+	// Variasi huruf besar dan whitespace menjadi satu tag
+	w := createArticle([]string{"Go", " go ", "GO", "Machine \t  Learning", "Cafe\u0301"})
+	suite.Equal(http.StatusOK, w.Code)
+
+	var createResp struct {
+		Data models.Article `json:"data"`
+	}
+	err := json.Unmarshal(w.Body.Bytes(), &createResp)
+	suite.NoError(err)
+
+	var names []string
+	for _, tag := range createResp.Data.LatestVersion.Tags {
+		names = append(names, tag.Name)
+	}
+	suite.ElementsMatch([]string{"go", "machine learning", "caf\u00e9"}, names)
+
+	// Nama kosong, terlalu panjang, atau berisi karakter terlarang ditolak
+	suite.Equal(http.StatusBadRequest, createArticle([]string{"   "}).Code)
+	suite.Equal(http.StatusBadRequest, createArticle([]string{"a,b"}).Code)
+	suite.Equal(http.StatusBadRequest, createArticle([]string{strings.Repeat("x", config.TagNameMaxLength()+1)}).Code)
+
+	// CreateTag memakai normalisasi yang sama
+	_, err = suite.tagService.CreateTag(models.CreateTagRequest{Name: "  Rust  "})
+	suite.NoError(err)
+	_, err = suite.tagService.CreateTag(models.CreateTagRequest{Name: "RUST"})
+	suite.Error(err)
+
+	// Tag lama yang belum ternormalisasi digabung oleh MergeDuplicateTags
+	python := models.Tag{Name: "Python"}
+	pythonUpper := models.Tag{Name: "PYTHON"}
+	suite.NoError(suite.db.Create(&python).Error)
+	suite.NoError(suite.db.Create(&pythonUpper).Error)
+	suite.NoError(suite.db.Create(&models.ArticleVersionTag{ArticleVersionID: createResp.Data.LatestVersionID, TagID: python.ID}).Error)
+	suite.NoError(suite.db.Create(&models.ArticleVersionTag{ArticleVersionID: createResp.Data.LatestVersionID, TagID: pythonUpper.ID}).Error)
+
+	groups, err := suite.tagService.MergeDuplicateTags(true)
+	suite.NoError(err)
+	suite.Require().Len(groups, 1)
+	suite.Equal("python", groups[0].Name)
+	suite.Equal(python.ID, groups[0].Canonical.ID)
+
+	_, err = suite.tagService.MergeDuplicateTags(false)
+	suite.NoError(err)
+
+	var tags []models.Tag
+	suite.db.Where("lower(name) = ?", "python").Find(&tags)
+	suite.Require().Len(tags, 1)
+	suite.Equal(python.ID, tags[0].ID)
+	suite.Equal("python", tags[0].Name)
+
+	var count int64
+	suite.db.Model(&models.ArticleVersionTag{}).
+		Where("article_version_id = ? AND tag_id = ?", createResp.Data.LatestVersionID, python.ID).
+		Count(&count)
+	suite.Equal(int64(1), count)
+
+	// Nama lama tetap diarahkan ke tag kanonik
+	w = createArticle([]string{"PYTHON"})
+	suite.Equal(http.StatusOK, w.Code)
+	err = json.Unmarshal(w.Body.Bytes(), &createResp)
+	suite.NoError(err)
+	suite.Require().Len(createResp.Data.LatestVersion.Tags, 1)
+	suite.Equal(python.ID, createResp.Data.LatestVersion.Tags[0].ID)
+}
+
+func (suite *IntegrationTestSuite) TestTagHierarchy() {
+	createTag := func(name string, parentID *uint) models.Tag {
+		tag, err := suite.tagService.CreateTag(models.CreateTagRequest{Name: name, ParentID: parentID})
+		suite.Require().NoError(err)
+		return *tag
+	}
+
+	programming := createTag("programming", nil)
+	goTag := createTag("go", &programming.ID)
+	createTag("rust", &programming.ID)
+	generics := createTag("generics", &goTag.ID)
+
+	// Tag tidak boleh menjadi parent dirinya sendiri atau turunannya
+	_, err := suite.tagService.SetTagParent(programming.ID, models.SetTagParentRequest{ParentID: &generics.ID})
+	suite.ErrorIs(err, repositories.ErrTagCycle)
+	_, err = suite.tagService.SetTagParent(goTag.ID, models.SetTagParentRequest{ParentID: &goTag.ID})
+	suite.ErrorIs(err, repositories.ErrTagCycle)
+
+	req := httptest.NewRequest("GET", "/api/v1/tags/tree", nil)
+	req.Header.Set("Authorization", "Bearer "+suite.token)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusOK, w.Code)
+
+	var treeResp struct {
+		Data []models.TagTreeNode `json:"data"`
+	}
+	err = json.Unmarshal(w.Body.Bytes(), &treeResp)
+	suite.NoError(err)
+
+	var root *models.TagTreeNode
+	for i := range treeResp.Data {
+		if treeResp.Data[i].ID == programming.ID {
+			root = &treeResp.Data[i]
+		}
+	}
+	suite.Require().NotNil(root)
+	suite.Require().Len(root.Children, 2)
+	suite.Equal("go", root.Children[0].Name)
+	suite.Equal("rust", root.Children[1].Name)
+	suite.Require().Len(root.Children[0].Children, 1)
+	suite.Equal("generics", root.Children[0].Children[0].Name)
+
+	for _, tags := range [][]string{{"go"}, {"rust"}, {"generics"}, {"programming", "go"}, {"cooking"}} {
+		body, _ := json.Marshal(models.CreateArticleRequest{
+			Title:   "Hierarchy " + strings.Join(tags, " "),
+			Content: "<p>Tree</p>",
+			Tags:    tags,
+		})
+		req := httptest.NewRequest("POST", "/api/v1/articles", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+suite.token)
+		w := httptest.NewRecorder()
+		suite.router.ServeHTTP(w, req)
+
+		var createResp struct {
+			Data models.Article `json:"data"`
+		}
+		suite.NoError(json.Unmarshal(w.Body.Bytes(), &createResp))
+		suite.Equal(http.StatusOK, suite.updateVersionStatus(suite.token, createResp.Data.ID, createResp.Data.LatestVersionID, models.StatusPublished, ""))
+	}
+
+	listTotal := func(query string) int64 {
+		req := httptest.NewRequest("GET", "/api/v1/public/articles?"+query, nil)
+		w := httptest.NewRecorder()
+		suite.router.ServeHTTP(w, req)
+		suite.Equal(http.StatusOK, w.Code)
+
+		var listResp struct {
+			Data struct {
+				Articles []models.Article `json:"articles"`
+				Total    int64            `json:"total"`
+			} `json:"data"`
+		}
+		suite.NoError(json.Unmarshal(w.Body.Bytes(), &listResp))
+		suite.Equal(listResp.Data.Total, int64(len(listResp.Data.Articles)))
+		return listResp.Data.Total
+	}
+
+	suite.Equal(int64(1), listTotal(fmt.Sprintf("tag_id=%d", programming.ID)))
+	suite.Equal(int64(4), listTotal(fmt.Sprintf("tag_id=%d&include_descendants=true", programming.ID)))
+	suite.Equal(int64(3), listTotal(fmt.Sprintf("tag_id=%d&include_descendants=true", goTag.ID)))
+
+	// Anak dari tag yang dihapus pindah ke parent tag tersebut
+	suite.NoError(suite.tagService.DeleteTag(goTag.ID))
+	moved, err := suite.tagService.GetTag(generics.ID)
+	suite.NoError(err)
+	suite.Require().NotNil(moved.ParentID)
+	suite.Equal(programming.ID, *moved.ParentID)
+}
+
+func (suite *IntegrationTestSuite) TestTagSuggestions() {
+	for _, tags := range [][]string{
+		{"go", "docker"},
+		{"go", "docker"},
+		{"go", "kubernetes"},
+		{"python", "pandas"},
+		{"python", "pandas"},
+		{"python", "docker"},
+	} {
+		body, _ := json.Marshal(models.CreateArticleRequest{
+			Title:   "Suggest " + strings.Join(tags, " "),
+			Content: "<p>Stats</p>",
+			Tags:    tags,
+		})
+		req := httptest.NewRequest("POST", "/api/v1/articles", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+suite.token)
+		w := httptest.NewRecorder()
+		suite.router.ServeHTTP(w, req)
+		suite.Require().Equal(http.StatusOK, w.Code)
+	}
+
+	suggest := func(payload models.SuggestTagsRequest) (int, []models.TagSuggestion) {
+		body, _ := json.Marshal(payload)
+		req := httptest.NewRequest("POST", "/api/v1/tags/suggest", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+suite.token)
+		w := httptest.NewRecorder()
+		suite.router.ServeHTTP(w, req)
+
+		var resp struct {
+			Data []models.TagSuggestion `json:"data"`
+		}
+		_ = json.Unmarshal(w.Body.Bytes(), &resp)
+		return w.Code, resp.Data
+	}
+
+	// PMI(go, kubernetes) = ln 2 lebih tinggi dari PMI(go, docker) = ln 4/3
+	code, suggestions := suggest(models.SuggestTagsRequest{Tags: []string{"Go"}})
+	suite.Equal(http.StatusOK, code)
+	suite.Require().Len(suggestions, 2)
+	suite.Equal("kubernetes", suggestions[0].Name)
+	suite.InDelta(math.Log(2), suggestions[0].Score, 1e-9)
+	suite.Equal("docker", suggestions[1].Name)
+	suite.Equal(2, suggestions[1].CoOccurrences)
+
+	// Tag yang disebut di isi draft ikut disarankan walau belum pernah muncul bersama
+	code, suggestions = suggest(models.SuggestTagsRequest{Tags: []string{"go"}, Content: "<p>Analisis dengan Pandas.</p>", Limit: 3})
+	suite.Equal(http.StatusOK, code)
+	suite.Require().Len(suggestions, 3)
+	suite.Equal("pandas", suggestions[2].Name)
+	suite.True(suggestions[2].Mentioned)
+
+	// Tanpa tag input, tag di isi draft menjadi dasar; docker berkorelasi negatif dengan python
+	code, suggestions = suggest(models.SuggestTagsRequest{Content: "Belajar python hari ini"})
+	suite.Equal(http.StatusOK, code)
+	suite.Require().Len(suggestions, 1)
+	suite.Equal("pandas", suggestions[0].Name)
+
+	code, _ = suggest(models.SuggestTagsRequest{})
+	suite.Equal(http.StatusBadRequest, code)
+
+	// Urutan mengikuti scorer yang dikonfigurasi: Jaccard(go, docker) = 2/4
+	// lebih tinggi dari Jaccard(go, kubernetes) = 1/3
+	jaccard, err := services.NewRelationshipScorer(services.RelationshipScorerJaccard)
+	suite.Require().NoError(err)
+	tagService := services.NewTagService(
+		repositories.NewTagRepository(suite.db),
+		repositories.NewArticleRepository(suite.db),
+		services.NewTagNormalizer(config.TagNameMaxLength(), config.TagCaseFolding(), config.TagDisallowedChars()),
+		jaccard,
+	)
+	suggestions, err = tagService.SuggestTags(models.SuggestTagsRequest{Tags: []string{"go"}})
+	suite.Require().NoError(err)
+	suite.Require().Len(suggestions, 2)
+	suite.Equal("docker", suggestions[0].Name)
+	suite.InDelta(0.5, suggestions[0].Score, 1e-9)
+	suite.Equal("kubernetes", suggestions[1].Name)
+	suite.InDelta(1.0/3, suggestions[1].Score, 1e-9)
+}
+
+func (suite *IntegrationTestSuite) TestRelatedArticles() {
+	createArticle := func(title string, tags []string, publish bool) models.Article {
+		body, _ := json.Marshal(models.CreateArticleRequest{
+			Title:   title,
+			Content: "<p>Related</p>",
+			Tags:    tags,
+		})
+		req := httptest.NewRequest("POST", "/api/v1/articles", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+suite.token)
+		w := httptest.NewRecorder()
+		suite.router.ServeHTTP(w, req)
+		suite.Require().Equal(http.StatusOK, w.Code)
+
+		var createResp struct {
+			Data models.Article `json:"data"`
+		}
+		suite.NoError(json.Unmarshal(w.Body.Bytes(), &createResp))
+		if publish {
+			suite.Equal(http.StatusOK, suite.updateVersionStatus(suite.token, createResp.Data.ID, createResp.Data.LatestVersionID, models.StatusPublished, ""))
+		}
+		return createResp.Data
+	}
+
+	source := createArticle("Related Source", []string{"go", "docker"}, true)
+	both := createArticle("Related Both", []string{"go", "docker"}, true)
+	goOnly := createArticle("Related Go", []string{"go"}, true)
+	kubernetes := createArticle("Related Kubernetes", []string{"go", "kubernetes"}, true)
+	createArticle("Related Python", []string{"python"}, true)
+	draft := createArticle("Related Draft", []string{"docker"}, false)
+
+	getRelated := func(router *gin.Engine, id uint, query string) (int, []models.RelatedArticle) {
+		req := httptest.NewRequest("GET", fmt.Sprintf("/api/v1/public/articles/%d/related%s", id, query), nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var resp struct {
+			Data []models.RelatedArticle `json:"data"`
+		}
+		_ = json.Unmarshal(w.Body.Bytes(), &resp)
+		return w.Code, resp.Data
+	}
+	ids := func(related []models.RelatedArticle) []uint {
+		result := make([]uint, 0, len(related))
+		for _, r := range related {
+			result = append(result, r.ID)
+		}
+		return result
+	}
+
+	// Bobot: docker (-ln 3/6) > go (-ln 4/6) = kubernetes (PMI dengan go, ln 1.5)
+	code, related := getRelated(suite.router, source.ID, "")
+	suite.Equal(http.StatusOK, code)
+	suite.Equal([]uint{both.ID, kubernetes.ID, goOnly.ID}, ids(related))
+	suite.Equal([]string{"go", "kubernetes"}, related[1].MatchedTags)
+	suite.InDelta(-math.Log(4.0/6)+math.Log(1.5), related[1].Score, 1e-9)
+	suite.NotContains(ids(related), draft.ID)
+
+	code, related = getRelated(suite.router, source.ID, "?limit=1")
+	suite.Equal(http.StatusOK, code)
+	suite.Equal([]uint{both.ID}, ids(related))
+
+	// Artikel yang belum published tidak punya rekomendasi
+	code, _ = getRelated(suite.router, draft.ID, "")
+	suite.Equal(http.StatusNotFound, code)
+
+	// Dengan recency decay, artikel lama turun ke bawah
+	suite.NoError(suite.db.Model(&models.ArticleVersion{}).
+		Where("id = ?", both.LatestVersionID).
+		Update("published_at", time.Now().Add(-48*time.Hour)).Error)
+	decayService := services.NewRelatedArticleService(repositories.NewArticleRepository(suite.db), services.NewContentRenderer(10), 5, time.Hour)
+	decayRouter := gin.New()
+	decayRouter.GET("/api/v1/public/articles/:id/related", handlers.NewRelatedArticleHandler(decayService).GetRelatedArticles)
+
+	code, related = getRelated(decayRouter, source.ID, "")
+	suite.Equal(http.StatusOK, code)
+	suite.Equal([]uint{kubernetes.ID, goOnly.ID, both.ID}, ids(related))
+}
+
+func (suite *IntegrationTestSuite) TestTagStatsStayInSync() {
+	articleRepo := repositories.NewArticleRepository(suite.db)
+	names := []string{"go", "docker", "kubernetes", "python", "golang"}
+
+	// Statistik bertahap harus selalu sama dengan agregasi langsung
+	assertInSync := func(step string) {
+		expectedFreq, err := articleRepo.GetTagFrequencies(names)
+		suite.Require().NoError(err)
+		actualFreq, err := articleRepo.GetTagStatFrequencies(names)
+		suite.Require().NoError(err)
+		suite.Equal(expectedFreq, actualFreq, step)
+
+		expectedPairs, err := articleRepo.GetTagPairCoOccurrences(names)
+		suite.Require().NoError(err)
+		actualPairs, err := articleRepo.GetTagStatPairCoOccurrences(names)
+		suite.Require().NoError(err)
+		suite.Equal(expectedPairs, actualPairs, step)
+	}
+
+	createArticle := func(title string, tags []string) models.Article {
+		body, _ := json.Marshal(models.CreateArticleRequest{Title: title, Content: "<p>Stats</p>", Tags: tags})
+		req := httptest.NewRequest("POST", "/api/v1/articles", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+suite.token)
+		w := httptest.NewRecorder()
+		suite.router.ServeHTTP(w, req)
+		suite.Require().Equal(http.StatusOK, w.Code)
+
+		var createResp struct {
+			Data models.Article `json:"data"`
+		}
+		suite.NoError(json.Unmarshal(w.Body.Bytes(), &createResp))
+		return createResp.Data
+	}
+
+	first := createArticle("Stats First", []string{"go", "docker"})
+	second := createArticle("Stats Second", []string{"go", "docker", "kubernetes"})
+	createArticle("Stats Third", []string{"python", "golang"})
+	assertInSync("create")
+
+	body, _ := json.Marshal(models.CreateArticleVersionRequest{
+		Title:         "Stats First v2",
+		Content:       "<p>Stats</p>",
+		Tags:          []string{"go", "kubernetes"},
+		BaseVersionID: &first.LatestVersionID,
+	})
+	req := httptest.NewRequest("POST", fmt.Sprintf("/api/v1/articles/%d/versions", first.ID), bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+suite.token)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.Require().Equal(http.StatusOK, w.Code)
+	assertInSync("new version")
+
+	req = httptest.NewRequest("POST", fmt.Sprintf("/api/v1/articles/%d/versions/%d/revert", first.ID, first.LatestVersionID), nil)
+	req.Header.Set("Authorization", "Bearer "+suite.token)
+	req.Header.Set("If-Match", suite.articleETag(suite.token, first.ID))
+	w = httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.Require().Equal(http.StatusOK, w.Code)
+	assertInSync("revert")
+
+	req = httptest.NewRequest("DELETE", fmt.Sprintf("/api/v1/articles/%d", second.ID), nil)
+	req.Header.Set("Authorization", "Bearer "+suite.token)
+	w = httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.Require().Equal(http.StatusOK, w.Code)
+	assertInSync("delete")
+
+	req = httptest.NewRequest("POST", fmt.Sprintf("/api/v1/admin/trash/articles/%d/restore", second.ID), nil)
+	req.Header.Set("Authorization", "Bearer "+suite.token)
+	w = httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.Require().Equal(http.StatusOK, w.Code)
+	assertInSync("restore")
+
+	tags, err := suite.tagService.GetTags()
+	suite.Require().NoError(err)
+	ids := map[string]uint{}
+	for _, tag := range tags {
+		ids[tag.Name] = tag.ID
+	}
+	_, err = suite.tagService.MergeTag(ids["golang"], models.MergeTagRequest{TargetID: ids["go"]})
+	suite.NoError(err)
+	assertInSync("merge")
+
+	freq, err := articleRepo.GetTagStatFrequencies([]string{"go"})
+	suite.NoError(err)
+	suite.Equal(3, freq["go"])
+
+	suite.NoError(articleRepo.RebuildTagStats())
+	assertInSync("rebuild")
+}
+
+func (suite *IntegrationTestSuite) TestTagTrendingRecompute() {
+	writerToken, _ := suite.registerUser("trendwriter", "trendwriter@example.com", models.RoleWriter)
+
+	body, _ := json.Marshal(models.CreateArticleRequest{
+		Title:   "Trending Article",
+		Content: "<p>Trending</p>",
+		Tags:    []string{"trending"},
+	})
+	req := httptest.NewRequest("POST", "/api/v1/articles", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+suite.token)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.Require().Equal(http.StatusOK, w.Code)
+
+	var createResp struct {
+		Data models.Article `json:"data"`
+	}
+	suite.NoError(json.Unmarshal(w.Body.Bytes(), &createResp))
+	suite.Equal(http.StatusOK, suite.updateVersionStatus(suite.token, createResp.Data.ID, createResp.Data.LatestVersionID, models.StatusPublished, ""))
+
+	tagByName := func(name string) models.Tag {
+		var tag models.Tag
+		suite.NoError(suite.db.Where("name = ?", name).First(&tag).Error)
+		return tag
+	}
+
+	// Publish tidak lagi menghitung ulang usage_count secara sinkron
+	before := tagByName("trending")
+	suite.Equal(0, before.UsageCount)
+	suite.Nil(before.LastUsedAt)
+
+	recompute := func(token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/api/v1/admin/tags/recompute-trending", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		suite.router.ServeHTTP(w, req)
+		return w
+	}
+
+	suite.Equal(http.StatusUnauthorized, recompute(writerToken).Code)
+
+	w = recompute(suite.token)
+	suite.Equal(http.StatusOK, w.Code)
+	var recomputeResp struct {
+		Data models.TagUsageRecompute `json:"data"`
+	}
+	suite.NoError(json.Unmarshal(w.Body.Bytes(), &recomputeResp))
+	suite.Equal(1, recomputeResp.Data.Updated)
+
+	after := tagByName("trending")
+	suite.Equal(1, after.UsageCount)
+	suite.Require().NotNil(after.LastUsedAt)
+	suite.Greater(after.TrendingScore, 0.0)
+	suite.LessOrEqual(after.TrendingScore, 1.0)
+	suite.True(before.UpdatedAt.Equal(after.UpdatedAt))
+
+	// Satu half-life kemudian skor tinggal setengah
+	trendingService := services.NewTagTrendingService(repositories.NewArticleRepository(suite.db), repositories.NewTagRepository(suite.db), time.Hour)
+	_, err := trendingService.RecomputeTagUsage(after.LastUsedAt.Add(time.Hour))
+	suite.NoError(err)
+	suite.InDelta(0.5, tagByName("trending").TrendingScore, 0.001)
+
+	// last_used_at tetap walaupun artikelnya sudah tidak published
+	suite.Equal(http.StatusOK, suite.updateVersionStatus(suite.token, createResp.Data.ID, createResp.Data.LatestVersionID, models.StatusArchivedVersion, ""))
+	suite.Equal(http.StatusOK, recompute(suite.token).Code)
+	archived := tagByName("trending")
+	suite.Equal(0, archived.UsageCount)
+	suite.Equal(0.0, archived.TrendingScore)
+	suite.Require().NotNil(archived.LastUsedAt)
+	suite.True(after.LastUsedAt.Equal(*archived.LastUsedAt))
+}
+
+func (suite *IntegrationTestSuite) TestTrendingTagsWindow() {
+	body, _ := json.Marshal(models.CreateArticleRequest{
+		Title:   "Trending Window",
+		Content: "<p>Trending</p>",
+		Tags:    []string{"rising", "steady"},
+	})
+	req := httptest.NewRequest("POST", "/api/v1/articles", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+suite.token)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.Require().Equal(http.StatusOK, w.Code)
+
+	var createResp struct {
+		Data models.Article `json:"data"`
+	}
+	suite.NoError(json.Unmarshal(w.Body.Bytes(), &createResp))
+	suite.Equal(http.StatusOK, suite.updateVersionStatus(suite.token, createResp.Data.ID, createResp.Data.LatestVersionID, models.StatusPublished, ""))
+
+	// Publish hari-hari sebelumnya untuk "steady"
+	var steady models.Tag
+	suite.Require().NoError(suite.db.Where("name = ?", "steady").First(&steady).Error)
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	for _, bucket := range []struct {
+		daysAgo int
+		count   int
+	}{{1, 2}, {8, 3}} {
+		suite.Require().NoError(suite.db.Exec(
+			"INSERT INTO tag_daily_usage (tag_id, day, publish_count) VALUES (?, ?, ?)",
+			steady.ID, today.AddDate(0, 0, -bucket.daysAgo).Format("2006-01-02"), bucket.count,
+		).Error)
+	}
+
+	getTrending := func(query string) (int, []models.TrendingTag) {
+		req := httptest.NewRequest("GET", "/api/v1/public/tags/trending?"+query, nil)
+		w := httptest.NewRecorder()
+		suite.router.ServeHTTP(w, req)
+
+		var resp struct {
+			Data []models.TrendingTag `json:"data"`
+		}
+		_ = json.Unmarshal(w.Body.Bytes(), &resp)
+		return w.Code, resp.Data
+	}
+
+	// 7d: steady 1+2 vs 3 minggu lalu, rising 1 vs 0
+	code, trending := getTrending("window=7d")
+	suite.Equal(http.StatusOK, code)
+	suite.Require().Len(trending, 2)
+	suite.Equal(models.TrendingTag{TagID: steady.ID, Name: "steady", Count: 3, PreviousCount: 3, Velocity: 0}, trending[0])
+	suite.Equal("rising", trending[1].Name)
+	suite.Equal(1, trending[1].Velocity)
+
+	code, trending = getTrending("window=7d&sort=velocity&limit=1")
+	suite.Equal(http.StatusOK, code)
+	suite.Require().Len(trending, 1)
+	suite.Equal("rising", trending[0].Name)
+
+	// 24h: hanya bucket hari ini dibanding kemarin
+	code, trending = getTrending("window=24h&sort=velocity")
+	suite.Equal(http.StatusOK, code)
+	suite.Require().Len(trending, 2)
+	suite.Equal("rising", trending[0].Name)
+	suite.Equal(models.TrendingTag{TagID: steady.ID, Name: "steady", Count: 1, PreviousCount: 2, Velocity: -1}, trending[1])
+
+	code, trending = getTrending("window=30d")
+	suite.Equal(http.StatusOK, code)
+	suite.Require().Len(trending, 2)
+	suite.Equal(6, trending[0].Count)
+
+	code, _ = getTrending("window=1y")
+	suite.Equal(http.StatusBadRequest, code)
+}
+
+func (suite *IntegrationTestSuite) TestRelationshipScorers() {
+	_, err := services.NewRelationshipScorer("cosine")
+	suite.Error(err)
+
+	// a dan b selalu muncul bersama saat a dipakai: 2 dari 8 artikel
+	cases := []struct {
+		name     string
+		positive float64
+		negative float64
+	}{
+		{services.RelationshipScorerPMI, math.Log(2), math.Log(0.5)},
+		{services.RelationshipScorerPPMI, math.Log(2), 0},
+		{services.RelationshipScorerNPMI, 0.5, -1.0 / 3},
+		{services.RelationshipScorerJaccard, 0.5, 1.0 / 7},
+	}
+	for _, tc := range cases {
+		scorer, err := services.NewRelationshipScorer(strings.ToUpper(tc.name))
+		suite.Require().NoError(err)
+		suite.Equal(tc.name, scorer.Name())
+
+		score, ok := scorer.PairScore(2, 2, 4, 8)
+		suite.True(ok, tc.name)
+		suite.InDelta(tc.positive, score, 1e-9, tc.name)
+
+		score, ok = scorer.PairScore(1, 4, 4, 8)
+		suite.True(ok, tc.name)
+		suite.InDelta(tc.negative, score, 1e-9, tc.name)
+
+		// Hanya Jaccard yang bisa menilai pasangan yang tidak pernah muncul bersama
+		score, ok = scorer.PairScore(0, 4, 4, 8)
+		if tc.name == services.RelationshipScorerJaccard {
+			suite.True(ok, tc.name)
+			suite.Zero(score, tc.name)
+		} else {
+			suite.False(ok, tc.name)
+		}
+
+		_, ok = scorer.PairScore(0, 0, 4, 8)
+		suite.False(ok, tc.name)
+	}
+
+	createArticle := func(title string, tags []string) models.Article {
+		body, _ := json.Marshal(models.CreateArticleRequest{Title: title, Content: "<p>Rescore</p>", Tags: tags})
+		req := httptest.NewRequest("POST", "/api/v1/articles", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+suite.token)
+		w := httptest.NewRecorder()
+		suite.router.ServeHTTP(w, req)
+		suite.Require().Equal(http.StatusOK, w.Code)
+
+		var createResp struct {
+			Data models.Article `json:"data"`
+		}
+		suite.NoError(json.Unmarshal(w.Body.Bytes(), &createResp))
+		return createResp.Data
+	}
+
+	first := createArticle("Rescore First", []string{"alpha", "beta"})
+	createArticle("Rescore Second", []string{"alpha", "beta", "gamma"})
+	createArticle("Rescore Third", []string{"gamma", "delta"})
+
+	// Skor lama (mis. dari algoritma sebelumnya) ditimpa semua
+	suite.Require().NoError(suite.db.Exec("UPDATE article_versions SET article_tag_relationship_score = 99").Error)
+
+	rescored, err := suite.articleService.RescoreArticleVersions()
+	suite.Require().NoError(err)
+	suite.Equal(3, rescored)
+
+	// alpha dan beta masing-masing di 2 dari 3 artikel, selalu bersama
+	var score float64
+	suite.Require().NoError(suite.db.Raw("SELECT article_tag_relationship_score FROM article_versions WHERE id = ?", first.LatestVersionID).Scan(&score).Error)
+	suite.InDelta(math.Log(1.5), score, 0.01)
+}
+
+func (suite *IntegrationTestSuite) TestScorePreview() {
+	createArticle := func(title string, tags []string) models.Article {
+		body, _ := json.Marshal(models.CreateArticleRequest{Title: title, Content: "<p>Preview</p>", Tags: tags})
+		req := httptest.NewRequest("POST", "/api/v1/articles", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+suite.token)
+		w := httptest.NewRecorder()
+		suite.router.ServeHTTP(w, req)
+		suite.Require().Equal(http.StatusOK, w.Code)
+
+		var createResp struct {
+			Data models.Article `json:"data"`
+		}
+		suite.NoError(json.Unmarshal(w.Body.Bytes(), &createResp))
+		return createResp.Data
+	}
+
+	preview := func(tags []string) (int, models.TagRelationshipScore) {
+		body, _ := json.Marshal(models.ScoreTagsRequest{Tags: tags})
+		req := httptest.NewRequest("POST", "/api/v1/articles/score-preview", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+suite.token)
+		w := httptest.NewRecorder()
+		suite.router.ServeHTTP(w, req)
+
+		var resp struct {
+			Data models.TagRelationshipScore `json:"data"`
+		}
+		if w.Code == http.StatusOK {
+			suite.NoError(json.Unmarshal(w.Body.Bytes(), &resp))
+		}
+		return w.Code, resp.Data
+	}
+
+	createArticle("Preview First", []string{"alpha", "beta"})
+	createArticle("Preview Second", []string{"alpha", "beta", "gamma"})
+	third := createArticle("Preview Third", []string{"gamma", "delta"})
+	createArticle("Preview Fourth", []string{"delta", "epsilon"})
+
+	// Nama dinormalisasi; tag yang belum ada ikut dipasangkan tapi tidak dihitung
+	code, result := preview([]string{"Alpha", "beta", "unknown", "alpha"})
+	suite.Require().Equal(http.StatusOK, code)
+	suite.Equal([]string{"alpha", "beta", "unknown"}, result.Tags)
+	suite.Equal(int64(4), result.TotalArticles)
+	suite.Len(result.Pairs, 3)
+	counted := 0
+	for _, pair := range result.Pairs {
+		if pair.Counted {
+			counted++
+			suite.Equal("alpha", pair.TagA)
+			suite.Equal("beta", pair.TagB)
+			suite.Equal(2, pair.CoOccurrences)
+		}
+	}
+	suite.Equal(1, counted)
+	suite.InDelta(math.Log(2), result.Score, 1e-9)
+
+	// Preview tidak membuat tag baru
+	var unknownCount int64
+	suite.NoError(suite.db.Model(&models.Tag{}).Where("name = ?", "unknown").Count(&unknownCount).Error)
+	suite.Zero(unknownCount)
+
+	// Skor versi baru dihitung dari tag versi itu sendiri, bukan tag versi sebelumnya
+	body, _ := json.Marshal(models.CreateArticleVersionRequest{
+		Title:         "Preview Third v2",
+		Content:       "<p>Preview</p>",
+		Tags:          []string{"alpha", "gamma"},
+		BaseVersionID: &third.LatestVersionID,
+	})
+	req := httptest.NewRequest("POST", fmt.Sprintf("/api/v1/articles/%d/versions", third.ID), bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+suite.token)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.Require().Equal(http.StatusOK, w.Code)
+
+	var versionResp struct {
+		Data models.ArticleVersion `json:"data"`
+	}
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &versionResp))
+
+	// alpha di 3 dari 4 artikel, gamma di 2, keduanya bersama di 2; pasangan
+	// tag versi sebelumnya (gamma, delta) sudah tidak pernah muncul bersama
+	code, result = preview([]string{"gamma", "alpha"})
+	suite.Require().Equal(http.StatusOK, code)
+	suite.InDelta(math.Log(4.0/3), result.Score, 1e-9)
+	suite.InDelta(result.Score, versionResp.Data.ArticleTagRelationshipScore, 0.01)
+
+	code, _ = preview([]string{"   "})
+	suite.Equal(http.StatusBadRequest, code)
+}
+
+func (suite *IntegrationTestSuite) TestCursorPagination() {
+	tagSets := [][]string{
+		{"go", "api"},
+		{"go", "api", "rest"},
+		{"python"},
+		{"go", "docker"},
+		{"rest", "api"},
+	}
+	for i, tags := range tagSets {
+		body, _ := json.Marshal(models.CreateArticleRequest{Title: fmt.Sprintf("Cursor %d", i), Content: "<p>Cursor</p>", Tags: tags})
+		req := httptest.NewRequest("POST", "/api/v1/articles", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+suite.token)
+		w := httptest.NewRecorder()
+		suite.router.ServeHTTP(w, req)
+		suite.Require().Equal(http.StatusOK, w.Code)
+	}
+
+	type listData struct {
+		Articles   []models.Article `json:"articles"`
+		Total      *int64           `json:"total"`
+		NextCursor string           `json:"next_cursor"`
+		Paging     struct {
+			TotalRecords int               `json:"total_records"`
+			TotalPages   int               `json:"total_pages"`
+			Links        map[string]string `json:"links"`
+		} `json:"paging"`
+	}
+	list := func(query string) (int, listData) {
+		req := httptest.NewRequest("GET", "/api/v1/articles?status=draft&"+query, nil)
+		req.Header.Set("Authorization", "Bearer "+suite.token)
+		w := httptest.NewRecorder()
+		suite.router.ServeHTTP(w, req)
+
+		var resp struct {
+			Data listData `json:"data"`
+		}
+		if w.Code == http.StatusOK {
+			suite.NoError(json.Unmarshal(w.Body.Bytes(), &resp))
+		}
+		return w.Code, resp.Data
+	}
+
+	for _, sort := range []string{"sort_by=created_at&sort_order=desc", "sort_by=title&sort_order=asc", "sort_by=article_tag_relationship_score&sort_order=desc"} {
+		code, all := list(sort + "&limit=10")
+		suite.Require().Equal(http.StatusOK, code, sort)
+		suite.Require().Len(all.Articles, 5, sort)
+
+		// Mengikuti next_cursor harus menghasilkan urutan yang sama tanpa duplikat
+		var ids []uint
+		cursor := ""
+		for pages := 0; pages < 5; pages++ {
+			query := sort + "&limit=2"
+			if cursor != "" {
+				query += "&cursor=" + cursor
+			}
+			code, page := list(query)
+			suite.Require().Equal(http.StatusOK, code, sort)
+			if cursor != "" {
+				suite.Nil(page.Total, sort)
+			}
+			for _, article := range page.Articles {
+				ids = append(ids, article.ID)
+			}
+			cursor = page.NextCursor
+			if cursor == "" {
+				break
+			}
+		}
+		suite.Require().Len(ids, 5, sort)
+		for i, article := range all.Articles {
+			suite.Equal(article.ID, ids[i], sort)
+		}
+	}
+
+	// Artikel yang dibuat di tengah paging (desc) tidak menggeser halaman berikutnya
+	_, first := list("sort_by=created_at&sort_order=desc&limit=2")
+	body, _ := json.Marshal(models.CreateArticleRequest{Title: "Cursor Late", Content: "<p>Cursor</p>", Tags: []string{"go"}})
+	req := httptest.NewRequest("POST", "/api/v1/articles", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+suite.token)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.Require().Equal(http.StatusOK, w.Code)
+
+	_, second := list("sort_by=created_at&sort_order=desc&limit=2&cursor=" + first.NextCursor)
+	suite.Require().Len(second.Articles, 2)
+	for _, article := range second.Articles {
+		suite.NotEqual("Cursor Late", article.Title)
+		suite.NotEqual(first.Articles[0].ID, article.ID)
+		suite.NotEqual(first.Articles[1].ID, article.ID)
+	}
+
+	// Mode page/limit tetap ada beserta link paging
+	code, paged := list("sort_by=created_at&sort_order=desc&limit=2&page=2")
+	suite.Require().Equal(http.StatusOK, code)
+	suite.Require().NotNil(paged.Total)
+	suite.Equal(int64(6), *paged.Total)
+	suite.Equal(6, paged.Paging.TotalRecords)
+	suite.Equal(3, paged.Paging.TotalPages)
+	suite.Contains(paged.Paging.Links["next"], "page=3")
+	suite.Contains(paged.Paging.Links["next"], "status=draft")
+	suite.Contains(paged.Paging.Links["previous"], "page=1")
+
+	// Cursor dari urutan lain ditolak
+	code, _ = list("sort_by=title&sort_order=asc&limit=2&cursor=" + first.NextCursor)
+	suite.Equal(http.StatusBadRequest, code)
+	code, _ = list("sort_by=created_at&sort_order=desc&limit=2&cursor=not-a-cursor")
+	suite.Equal(http.StatusBadRequest, code)
+}
+
+func (suite *IntegrationTestSuite) TestStatusChangeInvalidatesETag() {
+	writerToken, _ := suite.registerUser("writer", "writer@example.com", models.RoleWriter)
+	editorToken, _ := suite.registerUser("editor", "editor@example.com", models.RoleEditor)
+
+	body, _ := json.Marshal(models.CreateArticleRequest{Title: "Stale Review", Content: "<p>Review</p>", Tags: []string{"review"}})
+	req := httptest.NewRequest("POST", "/api/v1/articles", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+writerToken)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.Require().Equal(http.StatusOK, w.Code)
+
+	var createResp struct {
+		Data models.Article `json:"data"`
+	}
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &createResp))
+	article := createResp.Data
+	suite.Require().Equal(http.StatusOK, suite.updateVersionStatus(writerToken, article.ID, article.LatestVersionID, models.StatusInReview, ""))
+
+	// Dua reviewer membaca ETag yang sama sebelum salah satunya mengubah status
+	staleETag := suite.articleETag(editorToken, article.ID)
+	sendStatus := func(status models.VersionStatus, comment string) int {
+		body, _ := json.Marshal(models.UpdateVersionStatusRequest{Status: status, Comment: comment})
+		req := httptest.NewRequest("PUT", fmt.Sprintf("/api/v1/articles/%d/versions/%d/status", article.ID, article.LatestVersionID), bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+editorToken)
+		req.Header.Set("If-Match", staleETag)
+		w := httptest.NewRecorder()
+		suite.router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// in_review -> approved tidak publish/unpublish, tapi tetap mengubah ETag
+	suite.Equal(http.StatusOK, sendStatus(models.StatusApproved, ""))
+	suite.NotEqual(staleETag, suite.articleETag(editorToken, article.ID))
+	suite.Equal(http.StatusPreconditionFailed, sendStatus(models.StatusChangesRequested, "Stale review"))
+
+	var reviewCount int64
+	suite.NoError(suite.db.Model(&models.VersionReview{}).Where("article_version_id = ?", article.LatestVersionID).Count(&reviewCount).Error)
+	suite.Equal(int64(2), reviewCount)
+}
+
+func (suite *IntegrationTestSuite) TestScheduleDroppedAfterStatusChange() {
+	body, _ := json.Marshal(models.CreateArticleRequest{Title: "Dropped Schedule", Content: "<p>Schedule</p>", Tags: []string{"schedule"}})
+	req := httptest.NewRequest("POST", "/api/v1/articles", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+suite.token)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.Require().Equal(http.StatusOK, w.Code)
+
+	var createResp struct {
+		Data models.Article `json:"data"`
+	}
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &createResp))
+	article := createResp.Data
+	versionID := article.LatestVersionID
+
+	publishAt := time.Now().Add(time.Hour)
+	body, _ = json.Marshal(models.ScheduleVersionRequest{PublishAt: &publishAt})
+	req = httptest.NewRequest("PUT", fmt.Sprintf("/api/v1/articles/%d/versions/%d/schedule", article.ID, versionID), bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+suite.token)
+	w = httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.Require().Equal(http.StatusOK, w.Code)
+
+	// Versi terjadwal diarsipkan: jadwal publish ikut dihapus
+	suite.Require().Equal(http.StatusOK, suite.updateVersionStatus(suite.token, article.ID, versionID, models.StatusArchivedVersion, ""))
+	var version models.ArticleVersion
+	suite.Require().NoError(suite.db.First(&version, versionID).Error)
+	suite.Nil(version.PublishAt)
+
+	suite.NoError(suite.articleService.RunScheduledPublishes(time.Now().Add(2 * time.Hour)))
+	req = httptest.NewRequest("GET", fmt.Sprintf("/api/v1/public/articles/%d", article.ID), nil)
+	w = httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.NotEqual(http.StatusOK, w.Code)
+
+	// Jadwal yang transisinya sudah tidak valid dihapus tanpa mempublish versi
+	suite.Require().NoError(suite.db.Model(&models.ArticleVersion{}).Where("id = ?", versionID).Updates(map[string]interface{}{
+		"status":     models.StatusChangesRequested,
+		"publish_at": time.Now().UTC().Add(-time.Minute),
+	}).Error)
+	suite.NoError(suite.articleService.RunScheduledPublishes(time.Now()))
+
+	suite.Require().NoError(suite.db.First(&version, versionID).Error)
+	suite.Equal(models.StatusChangesRequested, version.Status)
+	suite.Nil(version.PublishAt)
+}
+
 func TestIntegrationSuite(t *testing.T) {
 	suite.Run(t, new(IntegrationTestSuite))
 }
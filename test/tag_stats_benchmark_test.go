@@ -0,0 +1,110 @@
+package tests
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"os"
+	"testing"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"cisdi-test-cms/repositories"
+)
+
+const (
+	benchArticles    = 5000
+	benchTags        = 300
+	benchTagsPerItem = 5
+)
+
+// BenchmarkTagRelationshipStats membandingkan data yang dibaca scorer PMI:
+// agregasi langsung ke seluruh artikel (GetTagFrequencies +
+// GetTagPairCoOccurrences) vs tabel statistik yang sudah dihitung
+// (GetTagStatFrequencies + GetTagStatPairCoOccurrences).
+//
+//	go test ./test -run '^$' -bench TagRelationshipStats
+func BenchmarkTagRelationshipStats(b *testing.B) {
+	dsn := "host=localhost port=5432 user=myuser password=mypassword dbname=cms_test_db sslmode=disable"
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		b.Skip("test database not available:", err)
+	}
+
+	dropTestTables(db)
+	if err := RunSQLFile(db, "../migration/init.sql"); err != nil {
+		b.Fatal("failed to migrate:", err)
+	}
+	defer dropTestTables(db)
+
+	seedTagStatsBenchmark(b, db)
+
+	articleRepo := repositories.NewArticleRepository(db)
+	if err := articleRepo.RebuildTagStats(); err != nil {
+		b.Fatal("failed to rebuild tag stats:", err)
+	}
+
+	// Set tag acak seukuran artikel pada umumnya
+	rng := rand.New(rand.NewSource(42))
+	tagSets := make([][]string, 100)
+	for i := range tagSets {
+		for j := 0; j < benchTagsPerItem; j++ {
+			tagSets[i] = append(tagSets[i], fmt.Sprintf("tag-%d", 1+rng.Intn(benchTags)))
+		}
+	}
+
+	// GetTagFrequencies mencetak query debug di setiap panggilan
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	b.Run("aggregate", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			tags := tagSets[i%len(tagSets)]
+			if _, err := articleRepo.GetTagFrequencies(tags); err != nil {
+				b.Fatal(err)
+			}
+			if _, err := articleRepo.GetTagPairCoOccurrences(tags); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("precomputed", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			tags := tagSets[i%len(tagSets)]
+			if _, err := articleRepo.GetTagStatFrequencies(tags); err != nil {
+				b.Fatal(err)
+			}
+			if _, err := articleRepo.GetTagStatPairCoOccurrences(tags); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+// seedTagStatsBenchmark mengisi artikel dengan satu versi dan beberapa tag acak
+// langsung lewat SQL agar setup tidak mendominasi waktu benchmark.
+func seedTagStatsBenchmark(b *testing.B, db *gorm.DB) {
+	b.Helper()
+
+	statements := []string{
+		"SELECT setseed(0.42)",
+		"INSERT INTO users (id, username, email, password, role) VALUES (1, 'bench', 'bench@example.com', 'x', 'admin')",
+		fmt.Sprintf("INSERT INTO tags (id, name) SELECT i, 'tag-' || i FROM generate_series(1, %d) i", benchTags),
+		fmt.Sprintf(`INSERT INTO articles (id, author_id, title, slug, latest_version_id)
+			SELECT i, 1, 'Bench ' || i, 'bench-' || i, i FROM generate_series(1, %d) i`, benchArticles),
+		fmt.Sprintf(`INSERT INTO article_versions (id, article_id, version_number, title, content)
+			SELECT i, i, 1, 'Bench ' || i, 'content' FROM generate_series(1, %d) i`, benchArticles),
+		fmt.Sprintf(`INSERT INTO article_version_tags (article_version_id, tag_id)
+			SELECT DISTINCT v, 1 + floor(random() * %d)::int
+			FROM generate_series(1, %d) v, generate_series(1, %d) k`, benchTags, benchArticles, benchTagsPerItem),
+	}
+	for _, statement := range statements {
+		if err := db.Exec(statement).Error; err != nil {
+			b.Fatal("failed to seed benchmark data:", err)
+		}
+	}
+}